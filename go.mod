@@ -7,8 +7,9 @@ require (
 	github.com/alecthomas/kong v0.2.12
 	github.com/go-resty/resty/v2 v2.4.0
 	github.com/howeyc/gopass v0.0.0-20190910152052-7cb4b85ec19c // indirect
-	github.com/jarcoal/httpmock v1.1.0 // indirect
+	github.com/jarcoal/httpmock v1.1.0
 	github.com/segmentio/go-prompt v1.2.0
 	github.com/stretchr/testify v1.7.0
 	golang.org/x/crypto v0.0.0-20201221181555-eec23a3978ad // indirect
+	golang.org/x/term v0.0.0-20201126162022-7de9c90e9dd1
 )
@@ -0,0 +1,311 @@
+// Code generated by mockgen from client.go. DO NOT EDIT.
+
+package acsmock
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/stretchr/testify/mock"
+)
+
+// Client is a mock of acs.Client
+type Client struct {
+	mock.Mock
+}
+
+var _ acs.Client = (*Client)(nil)
+
+// InstallApp ...
+func (m *Client) InstallApp(stack string, token string, packageFileName string, packageReader io.Reader) error {
+	ret := m.Called(stack, token, packageFileName, packageReader)
+	return ret.Error(0)
+}
+
+// DescribeApp ...
+func (m *Client) DescribeApp(stack string, appName string) (*acs.App, error) {
+	ret := m.Called(stack, appName)
+	r0, _ := ret.Get(0).(*acs.App)
+	return r0, ret.Error(1)
+}
+
+// ListApps ...
+func (m *Client) ListApps(stack string) ([]acs.App, error) {
+	ret := m.Called(stack)
+	r0, _ := ret.Get(0).([]acs.App)
+	return r0, ret.Error(1)
+}
+
+// UninstallApp ...
+func (m *Client) UninstallApp(stack string, appName string) error {
+	ret := m.Called(stack, appName)
+	return ret.Error(0)
+}
+
+// InstallSplunkbaseApp ...
+func (m *Client) InstallSplunkbaseApp(stack string, token string, splunkbaseID string, version string, licenseURL string) error {
+	ret := m.Called(stack, token, splunkbaseID, version, licenseURL)
+	return ret.Error(0)
+}
+
+// DescribeSplunkbaseApp ...
+func (m *Client) DescribeSplunkbaseApp(stack string, appName string) (*acs.App, error) {
+	ret := m.Called(stack, appName)
+	r0, _ := ret.Get(0).(*acs.App)
+	return r0, ret.Error(1)
+}
+
+// ListSplunkbaseApps ...
+func (m *Client) ListSplunkbaseApps(stack string) ([]acs.App, error) {
+	ret := m.Called(stack)
+	r0, _ := ret.Get(0).([]acs.App)
+	return r0, ret.Error(1)
+}
+
+// UpdateSplunkbaseApp ...
+func (m *Client) UpdateSplunkbaseApp(stack string, token string, appName string, version string, licenseURL string) error {
+	ret := m.Called(stack, token, appName, version, licenseURL)
+	return ret.Error(0)
+}
+
+// UninstallSplunkbaseApp ...
+func (m *Client) UninstallSplunkbaseApp(stack string, appName string) error {
+	ret := m.Called(stack, appName)
+	return ret.Error(0)
+}
+
+// CreateIndex ...
+func (m *Client) CreateIndex(stack string, index acs.Index) error {
+	ret := m.Called(stack, index)
+	return ret.Error(0)
+}
+
+// DescribeIndex ...
+func (m *Client) DescribeIndex(stack string, indexName string) (*acs.Index, error) {
+	ret := m.Called(stack, indexName)
+	r0, _ := ret.Get(0).(*acs.Index)
+	return r0, ret.Error(1)
+}
+
+// ListIndexes ...
+func (m *Client) ListIndexes(stack string) ([]acs.Index, error) {
+	ret := m.Called(stack)
+	r0, _ := ret.Get(0).([]acs.Index)
+	return r0, ret.Error(1)
+}
+
+// UpdateIndex ...
+func (m *Client) UpdateIndex(stack string, indexName string, settings acs.IndexSettings) error {
+	ret := m.Called(stack, indexName, settings)
+	return ret.Error(0)
+}
+
+// DeleteIndex ...
+func (m *Client) DeleteIndex(stack string, indexName string) error {
+	ret := m.Called(stack, indexName)
+	return ret.Error(0)
+}
+
+// CreateHECToken ...
+func (m *Client) CreateHECToken(stack string, spec acs.HECTokenSpec) (*acs.HECToken, error) {
+	ret := m.Called(stack, spec)
+	r0, _ := ret.Get(0).(*acs.HECToken)
+	return r0, ret.Error(1)
+}
+
+// DescribeHECToken ...
+func (m *Client) DescribeHECToken(stack string, tokenName string) (*acs.HECToken, error) {
+	ret := m.Called(stack, tokenName)
+	r0, _ := ret.Get(0).(*acs.HECToken)
+	return r0, ret.Error(1)
+}
+
+// ListHECTokens ...
+func (m *Client) ListHECTokens(stack string) ([]acs.HECToken, error) {
+	ret := m.Called(stack)
+	r0, _ := ret.Get(0).([]acs.HECToken)
+	return r0, ret.Error(1)
+}
+
+// UpdateHECToken ...
+func (m *Client) UpdateHECToken(stack string, tokenName string, spec acs.HECTokenSpec) error {
+	ret := m.Called(stack, tokenName, spec)
+	return ret.Error(0)
+}
+
+// DeleteHECToken ...
+func (m *Client) DeleteHECToken(stack string, tokenName string) error {
+	ret := m.Called(stack, tokenName)
+	return ret.Error(0)
+}
+
+// ListAllowList ...
+func (m *Client) ListAllowList(stack string, feature string) ([]string, error) {
+	ret := m.Called(stack, feature)
+	r0, _ := ret.Get(0).([]string)
+	return r0, ret.Error(1)
+}
+
+// AddAllowListSubnets ...
+func (m *Client) AddAllowListSubnets(stack string, feature string, subnets []string) error {
+	ret := m.Called(stack, feature, subnets)
+	return ret.Error(0)
+}
+
+// RemoveAllowListSubnets ...
+func (m *Client) RemoveAllowListSubnets(stack string, feature string, subnets []string) error {
+	ret := m.Called(stack, feature, subnets)
+	return ret.Error(0)
+}
+
+// StackStatus ...
+func (m *Client) StackStatus(stack string) (*acs.StackStatus, error) {
+	ret := m.Called(stack)
+	r0, _ := ret.Get(0).(*acs.StackStatus)
+	return r0, ret.Error(1)
+}
+
+// RestartRequired ...
+func (m *Client) RestartRequired(stack string) (bool, error) {
+	ret := m.Called(stack)
+	r0, _ := ret.Get(0).(bool)
+	return r0, ret.Error(1)
+}
+
+// RestartStack ...
+func (m *Client) RestartStack(stack string) error {
+	ret := m.Called(stack)
+	return ret.Error(0)
+}
+
+// WaitForRestart ...
+func (m *Client) WaitForRestart(ctx context.Context, stack string, timeout time.Duration) error {
+	ret := m.Called(ctx, stack, timeout)
+	return ret.Error(0)
+}
+
+// CreateAuthToken ...
+func (m *Client) CreateAuthToken(stack string, request acs.AuthTokenRequest) (*acs.AuthToken, error) {
+	ret := m.Called(stack, request)
+	r0, _ := ret.Get(0).(*acs.AuthToken)
+	return r0, ret.Error(1)
+}
+
+// ListAuthTokens ...
+func (m *Client) ListAuthTokens(stack string) ([]acs.AuthToken, error) {
+	ret := m.Called(stack)
+	r0, _ := ret.Get(0).([]acs.AuthToken)
+	return r0, ret.Error(1)
+}
+
+// DeleteAuthToken ...
+func (m *Client) DeleteAuthToken(stack string, tokenID string) error {
+	ret := m.Called(stack, tokenID)
+	return ret.Error(0)
+}
+
+// CreateOutboundPort ...
+func (m *Client) CreateOutboundPort(stack string, port acs.OutboundPort, reason string) error {
+	ret := m.Called(stack, port, reason)
+	return ret.Error(0)
+}
+
+// DescribeOutboundPort ...
+func (m *Client) DescribeOutboundPort(stack string, port int) (*acs.OutboundPort, error) {
+	ret := m.Called(stack, port)
+	r0, _ := ret.Get(0).(*acs.OutboundPort)
+	return r0, ret.Error(1)
+}
+
+// ListOutboundPorts ...
+func (m *Client) ListOutboundPorts(stack string) ([]acs.OutboundPort, error) {
+	ret := m.Called(stack)
+	r0, _ := ret.Get(0).([]acs.OutboundPort)
+	return r0, ret.Error(1)
+}
+
+// DeleteOutboundPort ...
+func (m *Client) DeleteOutboundPort(stack string, port int, subnets []string) error {
+	ret := m.Called(stack, port, subnets)
+	return ret.Error(0)
+}
+
+// CreateRole ...
+func (m *Client) CreateRole(stack string, role acs.Role) error {
+	ret := m.Called(stack, role)
+	return ret.Error(0)
+}
+
+// DescribeRole ...
+func (m *Client) DescribeRole(stack string, roleName string) (*acs.Role, error) {
+	ret := m.Called(stack, roleName)
+	r0, _ := ret.Get(0).(*acs.Role)
+	return r0, ret.Error(1)
+}
+
+// ListRoles ...
+func (m *Client) ListRoles(stack string) ([]acs.Role, error) {
+	ret := m.Called(stack)
+	r0, _ := ret.Get(0).([]acs.Role)
+	return r0, ret.Error(1)
+}
+
+// UpdateRole ...
+func (m *Client) UpdateRole(stack string, roleName string, settings acs.RoleSettings) error {
+	ret := m.Called(stack, roleName, settings)
+	return ret.Error(0)
+}
+
+// DeleteRole ...
+func (m *Client) DeleteRole(stack string, roleName string) error {
+	ret := m.Called(stack, roleName)
+	return ret.Error(0)
+}
+
+// CreateUser ...
+func (m *Client) CreateUser(stack string, user acs.User) error {
+	ret := m.Called(stack, user)
+	return ret.Error(0)
+}
+
+// DescribeUser ...
+func (m *Client) DescribeUser(stack string, userName string) (*acs.User, error) {
+	ret := m.Called(stack, userName)
+	r0, _ := ret.Get(0).(*acs.User)
+	return r0, ret.Error(1)
+}
+
+// ListUsers ...
+func (m *Client) ListUsers(stack string) ([]acs.User, error) {
+	ret := m.Called(stack)
+	r0, _ := ret.Get(0).([]acs.User)
+	return r0, ret.Error(1)
+}
+
+// UpdateUser ...
+func (m *Client) UpdateUser(stack string, userName string, settings acs.UserSettings) error {
+	ret := m.Called(stack, userName, settings)
+	return ret.Error(0)
+}
+
+// DeleteUser ...
+func (m *Client) DeleteUser(stack string, userName string) error {
+	ret := m.Called(stack, userName)
+	return ret.Error(0)
+}
+
+// ListMaintenanceWindows ...
+func (m *Client) ListMaintenanceWindows(stack string) ([]acs.MaintenanceWindow, error) {
+	ret := m.Called(stack)
+	r0, _ := ret.Get(0).([]acs.MaintenanceWindow)
+	return r0, ret.Error(1)
+}
+
+// Request ...
+func (m *Client) Request(stack string, method string, path string, body []byte) (*acs.RawResponse, error) {
+	ret := m.Called(stack, method, path, body)
+	r0, _ := ret.Get(0).(*acs.RawResponse)
+	return r0, ret.Error(1)
+}
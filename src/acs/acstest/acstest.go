@@ -0,0 +1,398 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package acstest provides a fake ACS for testing clients of the acs package without a splunk cloud stack.
+//
+// The fake implements the private app endpoints of classic and victoria stacks statefully, along with the
+// status and maintenance window endpoints consulted around installs. The apps installed through the classic
+// and the victoria endpoints are kept apart, and the status reports the experience the server was built for. It can be served with httptest or on
+// any listener, e.g. by `cloudCtl fake-acs`.
+package acstest
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+)
+
+// Failure is an ACS error the server responds with instead of handling matching requests
+type Failure struct {
+	// Method matches any method if empty
+	Method string
+	// Path matches any path if empty, otherwise the request path must end with it, e.g. "/apps/victoria"
+	Path        string
+	Status      int
+	Code        string
+	Description string
+	// Times is the number of requests to fail, 0 fails every matching request
+	Times int
+}
+
+// Server is a fake ACS, it is an http.Handler
+type Server struct {
+	mu       sync.Mutex
+	victoria bool
+	apps     map[string]*stackApps
+	failures []*Failure
+	latency  time.Duration
+	token    string
+	requests int
+}
+
+// stackApps holds the apps installed on a stack through the classic and the victoria endpoints
+type stackApps struct {
+	classic  map[string]acs.App
+	victoria map[string]acs.App
+}
+
+// New creates an empty fake ACS for classic stacks
+func New() *Server {
+	return &Server{
+		apps: map[string]*stackApps{},
+	}
+}
+
+// NewVictoria creates an empty fake ACS for victoria stacks
+func NewVictoria() *Server {
+	s := New()
+	s.victoria = true
+	return s
+}
+
+// NewServer starts an httptest server for a new classic fake ACS, the caller must close the httptest server
+func NewServer() (*Server, *httptest.Server) {
+	s := New()
+	return s, httptest.NewServer(s)
+}
+
+// NewVictoriaServer starts an httptest server for a new victoria fake ACS, the caller must close the httptest
+// server
+func NewVictoriaServer() (*Server, *httptest.Server) {
+	s := NewVictoria()
+	return s, httptest.NewServer(s)
+}
+
+// SetLatency delays every response by d
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// SetToken makes the server reject requests which are not authenticated with the bearer token, any token is
+// accepted by default
+func (s *Server) SetToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+// Fail injects a failure for the requests matching f
+func (s *Server) Fail(f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, &f)
+}
+
+// AddApp installs an app on the stack without going through the api, the app is installed for the experience
+// of the server
+func (s *Server) AddApp(stack string, app acs.App) error {
+	if app.Name == nil || *app.Name == "" {
+		return fmt.Errorf("app has no name")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stackApps(stack, s.victoria)[*app.Name] = app
+	return nil
+}
+
+// Apps returns the apps installed on the stack for the experience of the server, sorted by name
+func (s *Server) Apps(stack string) []acs.App {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedApps(s.stackApps(stack, s.victoria))
+}
+
+// Requests returns the number of requests the server received
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// stackApps returns the apps of the stack installed through the endpoints of the experience, the caller must
+// hold the lock
+func (s *Server) stackApps(stack string, victoria bool) map[string]acs.App {
+	apps, ok := s.apps[stack]
+	if !ok {
+		apps = &stackApps{classic: map[string]acs.App{}, victoria: map[string]acs.App{}}
+		s.apps[stack] = apps
+	}
+	if victoria {
+		return apps.victoria
+	}
+	return apps.classic
+}
+
+func sortedApps(installed map[string]acs.App) []acs.App {
+	apps := []acs.App{}
+	for _, app := range installed {
+		apps = append(apps, app)
+	}
+	sort.Slice(apps, func(i, j int) bool { return *apps[i].Name < *apps[j].Name })
+	return apps
+}
+
+// ServeHTTP ...
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	// like ACS, every response carries the id of its request
+	w.Header().Set("X-Request-Id", fmt.Sprintf("fake-%d", s.requests))
+	latency := s.latency
+	token := s.token
+	failure := s.matchFailure(r)
+	s.mu.Unlock()
+
+	time.Sleep(latency)
+	if token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+		writeError(w, http.StatusUnauthorized, "401-unauthorized", "invalid or missing token")
+		return
+	}
+	if failure != nil {
+		writeError(w, failure.Status, failure.Code, failure.Description)
+		return
+	}
+
+	// paths look like /{stack}/adminconfig/v2/{resource}
+	parts := strings.SplitN(strings.Trim(r.URL.Path, "/"), "/", 4)
+	if len(parts) < 4 || parts[1] != "adminconfig" || parts[2] != "v2" {
+		writeError(w, http.StatusNotFound, "404-not-found", fmt.Sprintf("unknown path %s", r.URL.Path))
+		return
+	}
+	stack, resource := parts[0], parts[3]
+	switch {
+	case resource == "status":
+		s.status(w, r)
+	case resource == "maintenance-windows/schedules":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"schedules": []acs.MaintenanceWindow{}})
+	case resource == "apps" || resource == "apps/victoria":
+		s.appsCollection(w, r, stack, resource == "apps/victoria")
+	case strings.HasPrefix(resource, "apps/victoria/"):
+		s.app(w, r, stack, true, strings.TrimPrefix(resource, "apps/victoria/"))
+	case strings.HasPrefix(resource, "apps/"):
+		s.app(w, r, stack, false, strings.TrimPrefix(resource, "apps/"))
+	default:
+		writeError(w, http.StatusNotFound, "404-not-found", fmt.Sprintf("unknown path %s", r.URL.Path))
+	}
+}
+
+// matchFailure returns the failure to respond to r with, if any, the caller must hold the lock
+func (s *Server) matchFailure(r *http.Request) *Failure {
+	for i, f := range s.failures {
+		if f.Method != "" && !strings.EqualFold(f.Method, r.Method) {
+			continue
+		}
+		if f.Path != "" && !strings.HasSuffix(r.URL.Path, f.Path) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func (s *Server) status(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "405-method-not-allowed", r.Method)
+		return
+	}
+	status := acs.StackStatus{}
+	status.Infrastructure.StackType = "classic"
+	if s.victoria {
+		status.Infrastructure.StackType = "victoria"
+	}
+	status.Infrastructure.StackStatus = acs.StackStatusReady
+	status.Infrastructure.StackVersion = acs.MinVictoriaVersion
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (s *Server) appsCollection(w http.ResponseWriter, r *http.Request, stack string, victoria bool) {
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.Lock()
+		apps := sortedApps(s.stackApps(stack, victoria))
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]interface{}{"apps": apps})
+	case http.MethodPost:
+		s.install(w, r, stack, victoria)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "405-method-not-allowed", r.Method)
+	}
+}
+
+func (s *Server) install(w http.ResponseWriter, r *http.Request, stack string, victoria bool) {
+	if r.Header.Get("ACS-Legal-Ack") != "Y" {
+		writeError(w, http.StatusBadRequest, "400-bad-request", "the ACS-Legal-Ack header must be set to Y")
+		return
+	}
+	var pkg io.Reader
+	if victoria {
+		if r.Header.Get("X-Splunk-Authorization") == "" {
+			writeError(w, http.StatusUnauthorized, "401-unauthorized", "missing X-Splunk-Authorization header")
+			return
+		}
+		pkg = r.Body
+	} else {
+		if r.FormValue("token") == "" {
+			writeError(w, http.StatusUnauthorized, "401-unauthorized", "missing token form field")
+			return
+		}
+		f, _, err := r.FormFile("package")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "400-bad-request", fmt.Sprintf("missing package: %s", err))
+			return
+		}
+		defer f.Close()
+		pkg = f
+	}
+	app, err := readPackage(pkg)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "400-invalid-package", err.Error())
+		return
+	}
+	s.mu.Lock()
+	s.stackApps(stack, victoria)[*app.Name] = *app
+	s.mu.Unlock()
+	writeJSON(w, http.StatusAccepted, app)
+}
+
+func (s *Server) app(w http.ResponseWriter, r *http.Request, stack string, victoria bool, name string) {
+	s.mu.Lock()
+	apps := s.stackApps(stack, victoria)
+	app, ok := apps[name]
+	if ok && r.Method == http.MethodDelete {
+		delete(apps, name)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "404-object-not-found", fmt.Sprintf("app %s not found", name))
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, app)
+	case http.MethodDelete:
+		writeJSON(w, http.StatusOK, app)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "405-method-not-allowed", r.Method)
+	}
+}
+
+// readPackage derives the app from a tar.gz app package: the name is its top-level directory, the label and
+// version come from default/app.conf
+func readPackage(r io.Reader) (*acs.App, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("package is not gzipped: %s", err)
+	}
+	tr := tar.NewReader(gz)
+	var name, label, version string
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("package is not a tar archive: %s", err)
+		}
+		p := path.Clean(strings.TrimPrefix(h.Name, "./"))
+		if name == "" {
+			name = strings.SplitN(p, "/", 2)[0]
+		}
+		if p == path.Join(name, "default", "app.conf") {
+			data, err := ioutil.ReadAll(tr)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read app.conf: %s", err)
+			}
+			label, version = parseAppConf(string(data))
+		}
+	}
+	if name == "" || name == "." {
+		return nil, fmt.Errorf("package is empty")
+	}
+	app := &acs.App{Name: &name, Status: "installed"}
+	if label != "" {
+		app.Label = &label
+	}
+	if version != "" {
+		app.Version = &version
+	}
+	return app, nil
+}
+
+// parseAppConf returns the [ui] label and [launcher] version of an app.conf
+func parseAppConf(conf string) (label, version string) {
+	var stanza string
+	scanner := bufio.NewScanner(strings.NewReader(conf))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			stanza = strings.Trim(line, "[]")
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		switch {
+		case stanza == "ui" && key == "label":
+			label = value
+		case stanza == "launcher" && key == "version":
+			version = value
+		}
+	}
+	return label, version
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError responds with an error shaped like the ones of ACS
+func writeError(w http.ResponseWriter, status int, code, description string) {
+	writeJSON(w, status, map[string]string{"code": code, "description": description})
+}
@@ -0,0 +1,53 @@
+package acstest
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddApp(t *testing.T) {
+	assert := assert.New(t)
+	fake := New()
+	assert.EqualError(fake.AddApp("test-stack", acs.App{Status: "installed"}), "app has no name")
+	assert.Empty(fake.Apps("test-stack"))
+
+	name := "testapp"
+	assert.Nil(fake.AddApp("test-stack", acs.App{Name: &name, Status: "installed"}))
+	assert.Len(fake.Apps("test-stack"), 1)
+}
+
+func TestExperiences(t *testing.T) {
+	assert := assert.New(t)
+	name := "testapp"
+	for _, tc := range []struct {
+		fake                *Server
+		stackType, mismatch string
+	}{
+		{fake: New(), stackType: "classic", mismatch: "victoria"},
+		{fake: NewVictoria(), stackType: "victoria", mismatch: "classic"},
+	} {
+		srv := httptest.NewServer(tc.fake)
+		assert.Nil(tc.fake.AddApp("test-stack", acs.App{Name: &name, Status: "installed"}))
+
+		var cli acs.Client = acs.NewClassicWithURL(srv.URL, "stack-token")
+		other := acs.NewVictoriaWithURL(srv.URL, "stack-token")
+		if tc.stackType == "victoria" {
+			cli, other = other, cli
+		}
+		status, err := cli.StackStatus("test-stack")
+		assert.Nil(err)
+		assert.Equal(tc.stackType, status.Infrastructure.StackType)
+
+		// the apps of one experience are not listed by the endpoints of the other
+		apps, err := cli.ListApps("test-stack")
+		assert.Nil(err)
+		assert.Len(apps, 1)
+		apps, err = other.ListApps("test-stack")
+		assert.Nil(err, tc.mismatch)
+		assert.Empty(apps, tc.mismatch)
+		srv.Close()
+	}
+}
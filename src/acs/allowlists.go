@@ -0,0 +1,144 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acs
+
+import (
+	"fmt"
+	"net"
+	"sort"
+)
+
+// The features which can be restricted with an ip allow list
+const (
+	AllowListFeatureACS       = "acs"
+	AllowListFeatureSearchAPI = "search-api"
+	AllowListFeatureHEC       = "hec"
+	AllowListFeatureS2S       = "s2s"
+	AllowListFeatureSearchUI  = "search-ui"
+	AllowListFeatureIDMAPI    = "idm-api"
+	AllowListFeatureIDMUI     = "idm-ui"
+)
+
+type allowListRequest struct {
+	Subnets []string `json:"subnets"`
+}
+
+// ListAllowList returns the subnets allowed to access a feature of the stack
+func (c *client) ListAllowList(stack, feature string) ([]string, error) {
+	resp, err := c.resty.R().SetResult(&allowListRequest{}).
+		Get(fmt.Sprintf("/%s/adminconfig/v2/access/%s/ipallowlists", stack, feature))
+	if err != nil {
+		return nil, fmt.Errorf("error while listing allow list: %s", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("error while listing allow list: %s: %s", resp.Status(), resp.String())
+	}
+	allowList, ok := resp.Result().(*allowListRequest)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing response")
+	}
+	return allowList.Subnets, nil
+}
+
+// AddAllowListSubnets allows the subnets to access a feature of the stack
+func (c *client) AddAllowListSubnets(stack, feature string, subnets []string) error {
+	if err := validateSubnetChange(subnets); err != nil {
+		return err
+	}
+	resp, err := c.resty.R().SetBody(allowListRequest{Subnets: subnets}).
+		Post(fmt.Sprintf("/%s/adminconfig/v2/access/%s/ipallowlists", stack, feature))
+	if err != nil {
+		return fmt.Errorf("error while adding subnets to allow list: %s", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("error while adding subnets to allow list: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
+
+// RemoveAllowListSubnets removes the subnets from the allow list of a feature of the stack
+func (c *client) RemoveAllowListSubnets(stack, feature string, subnets []string) error {
+	if err := validateSubnetChange(subnets); err != nil {
+		return err
+	}
+	resp, err := c.resty.R().SetBody(allowListRequest{Subnets: subnets}).
+		Delete(fmt.Sprintf("/%s/adminconfig/v2/access/%s/ipallowlists", stack, feature))
+	if err != nil {
+		return fmt.Errorf("error while removing subnets from allow list: %s", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("error while removing subnets from allow list: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
+
+// ValidateSubnets checks that every subnet is in CIDR notation, an empty list is valid, i.e. an allow list
+// allowing nothing
+func ValidateSubnets(subnets []string) error {
+	for _, subnet := range subnets {
+		if _, _, err := net.ParseCIDR(subnet); err != nil {
+			return fmt.Errorf("invalid subnet %q: not in CIDR notation", subnet)
+		}
+	}
+	return nil
+}
+
+// validateSubnetChange checks the subnets added to or removed from a list, which can't be empty
+func validateSubnetChange(subnets []string) error {
+	if len(subnets) == 0 {
+		return fmt.Errorf("no subnets given")
+	}
+	return ValidateSubnets(subnets)
+}
+
+// DiffSubnets returns the subnets which must be added to and removed from the current allow list so it
+// matches the desired one. Subnets are compared by the network they denote, so "10.0.0.1/24" matches
+// "10.0.0.0/24".
+func DiffSubnets(current, desired []string) (toAdd []string, toRemove []string, err error) {
+	currentSet, err := subnetSet(current)
+	if err != nil {
+		return nil, nil, err
+	}
+	desiredSet, err := subnetSet(desired)
+	if err != nil {
+		return nil, nil, err
+	}
+	for network, subnet := range desiredSet {
+		if _, ok := currentSet[network]; !ok {
+			toAdd = append(toAdd, subnet)
+		}
+	}
+	for network, subnet := range currentSet {
+		if _, ok := desiredSet[network]; !ok {
+			toRemove = append(toRemove, subnet)
+		}
+	}
+	sort.Strings(toAdd)
+	sort.Strings(toRemove)
+	return toAdd, toRemove, nil
+}
+
+// subnetSet maps the network of every subnet to the subnet as it was given
+func subnetSet(subnets []string) (map[string]string, error) {
+	set := make(map[string]string, len(subnets))
+	for _, subnet := range subnets {
+		_, network, err := net.ParseCIDR(subnet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subnet %q: not in CIDR notation", subnet)
+		}
+		set[network.String()] = subnet
+	}
+	return set, nil
+}
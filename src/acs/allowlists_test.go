@@ -0,0 +1,36 @@
+package acs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSubnets(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(ValidateSubnets([]string{"10.0.0.0/24", "2001:db8::/32"}))
+	assert.Error(ValidateSubnets([]string{"10.0.0.0/24", "10.0.0.1"}))
+	assert.NoError(ValidateSubnets(nil))
+	assert.EqualError(validateSubnetChange(nil), "no subnets given")
+	assert.Error(validateSubnetChange([]string{"10.0.0.1"}))
+}
+
+func TestDiffSubnets(t *testing.T) {
+	assert := assert.New(t)
+
+	toAdd, toRemove, err := DiffSubnets(
+		[]string{"10.0.0.0/24", "192.168.0.0/16"},
+		[]string{"10.0.0.1/24", "172.16.0.0/12"})
+	assert.Nil(err)
+	assert.Equal([]string{"172.16.0.0/12"}, toAdd)
+	assert.Equal([]string{"192.168.0.0/16"}, toRemove)
+
+	toAdd, toRemove, err = DiffSubnets([]string{"10.0.0.0/24"}, []string{"10.0.0.0/24"})
+	assert.Nil(err)
+	assert.Empty(toAdd)
+	assert.Empty(toRemove)
+
+	_, _, err = DiffSubnets(nil, []string{"foo"})
+	assert.Error(err)
+}
@@ -15,17 +15,79 @@
 package acs
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/splunk/acs-privateapps-demo/src/upload"
 )
 
+//go:generate go run ../internal/mockgen -source client.go -interface Client -package acsmock -out acsmock/client.go
+
+// Client is the interface of the acs clients of classic and victoria stacks. Failed requests are not retried,
+// except by Request which retries idempotent requests (GET, HEAD, PUT and DELETE) up to 3 times when they
+// fail to send or ACS answers 429, 502, 503 or 504.
 type Client interface {
 	InstallApp(stack, token, packageFileName string, packageReader io.Reader) error
 	DescribeApp(stack string, appName string) (*App, error)
 	ListApps(stack string) ([]App, error)
 	UninstallApp(stack string, appName string) error
+
+	InstallSplunkbaseApp(stack, token, splunkbaseID, version, licenseURL string) error
+	DescribeSplunkbaseApp(stack string, appName string) (*App, error)
+	ListSplunkbaseApps(stack string) ([]App, error)
+	UpdateSplunkbaseApp(stack, token, appName, version, licenseURL string) error
+	UninstallSplunkbaseApp(stack string, appName string) error
+
+	CreateIndex(stack string, index Index) error
+	DescribeIndex(stack string, indexName string) (*Index, error)
+	ListIndexes(stack string) ([]Index, error)
+	UpdateIndex(stack string, indexName string, settings IndexSettings) error
+	DeleteIndex(stack string, indexName string) error
+
+	CreateHECToken(stack string, spec HECTokenSpec) (*HECToken, error)
+	DescribeHECToken(stack string, tokenName string) (*HECToken, error)
+	ListHECTokens(stack string) ([]HECToken, error)
+	UpdateHECToken(stack string, tokenName string, spec HECTokenSpec) error
+	DeleteHECToken(stack string, tokenName string) error
+
+	ListAllowList(stack, feature string) ([]string, error)
+	AddAllowListSubnets(stack, feature string, subnets []string) error
+	RemoveAllowListSubnets(stack, feature string, subnets []string) error
+
+	StackStatus(stack string) (*StackStatus, error)
+	RestartRequired(stack string) (bool, error)
+	RestartStack(stack string) error
+	WaitForRestart(ctx context.Context, stack string, timeout time.Duration) error
+
+	CreateAuthToken(stack string, request AuthTokenRequest) (*AuthToken, error)
+	ListAuthTokens(stack string) ([]AuthToken, error)
+	DeleteAuthToken(stack string, tokenID string) error
+
+	CreateOutboundPort(stack string, port OutboundPort, reason string) error
+	DescribeOutboundPort(stack string, port int) (*OutboundPort, error)
+	ListOutboundPorts(stack string) ([]OutboundPort, error)
+	DeleteOutboundPort(stack string, port int, subnets []string) error
+
+	CreateRole(stack string, role Role) error
+	DescribeRole(stack string, roleName string) (*Role, error)
+	ListRoles(stack string) ([]Role, error)
+	UpdateRole(stack string, roleName string, settings RoleSettings) error
+	DeleteRole(stack string, roleName string) error
+
+	CreateUser(stack string, user User) error
+	DescribeUser(stack string, userName string) (*User, error)
+	ListUsers(stack string) ([]User, error)
+	UpdateUser(stack string, userName string, settings UserSettings) error
+	DeleteUser(stack string, userName string) error
+
+	ListMaintenanceWindows(stack string) ([]MaintenanceWindow, error)
+
+	Request(stack, method, path string, body []byte) (*RawResponse, error)
 }
 
 // victoriaClient is a client used to interface with ACS for Victoria stacks
@@ -39,7 +101,10 @@ type classicClient struct {
 }
 
 type client struct {
-	resty *resty.Client
+	resty          *resty.Client
+	maxPackageSize int64
+	// raw sends the requests of Request, it retries the idempotent ones
+	raw *resty.Client
 }
 
 type acsError struct {
@@ -51,30 +116,71 @@ func (e *acsError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Code, e.Description)
 }
 
-func newClient(acsURL, token string) client {
+// the time to wait between retries, doubled on every attempt up to the max
+var (
+	retryWaitTime    = 2 * time.Second
+	retryMaxWaitTime = 30 * time.Second
+)
+
+func newClient(acsURL, token string, opts []Option) client {
+	o := options{maxPackageSize: DefaultMaxPackageSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	r := o.http.Resty().SetHostURL(acsURL).SetError(&acsError{}).SetAuthScheme("Bearer").SetAuthToken(token).
+		SetPreRequestHook(upload.StreamBody)
+	raw := o.http.Resty().SetHostURL(acsURL).SetError(&acsError{}).SetAuthScheme("Bearer").SetAuthToken(token).
+		SetRetryCount(3).SetRetryWaitTime(retryWaitTime).SetRetryMaxWaitTime(retryMaxWaitTime).
+		AddRetryCondition(retryable)
 	return client{
-		resty: resty.New().SetHostURL(acsURL).SetError(&acsError{}).SetAuthScheme("Bearer").SetAuthToken(token),
+		resty:          r,
+		raw:            raw,
+		maxPackageSize: o.maxPackageSize,
 	}
 }
 
+// retryable reports whether a failed request of Request can safely be sent again. Non-idempotent requests are
+// never retried since ACS may have applied them, even when it answered 429.
+func retryable(resp *resty.Response, err error) bool {
+	if resp == nil || resp.Request == nil {
+		return false
+	}
+	switch resp.Request.Method {
+	case resty.MethodGet, resty.MethodHead, resty.MethodPut, resty.MethodDelete:
+	default:
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode() {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
 // NewVictoriaWithURL creates a new VictoriaClient
-func NewVictoriaWithURL(acsURL, token string) Client {
+func NewVictoriaWithURL(acsURL, token string, opts ...Option) Client {
 	return &victoriaClient{
-		client: newClient(acsURL, token),
+		client: newClient(acsURL, token, opts),
 	}
 }
 
 // NewClassicWithURL creates a new ClassicClient
-func NewClassicWithURL(acsURL, token string) Client {
+func NewClassicWithURL(acsURL, token string, opts ...Option) Client {
 	return &classicClient{
-		client: newClient(acsURL, token),
+		client: newClient(acsURL, token, opts),
 	}
 }
 
 // InstallApp installs an app on a classic stack
 func (c *classicClient) InstallApp(stack, token, packageFileName string, packageReader io.Reader) error {
-	resp, err := c.resty.R().SetFormData(map[string]string{"token": token}).
-		SetFileReader("package", packageFileName, packageReader).
+	body, contentType, err := upload.Multipart(url.Values{"token": {token}}, "package", packageFileName, packageReader)
+	if err != nil {
+		return fmt.Errorf("error while installing app: %s", err)
+	}
+	resp, err := upload.Stream(c.resty.R(), body).SetHeader("Content-Type", contentType).
 		SetHeader("ACS-Legal-Ack", "Y").
 		Post("/" + stack + "/adminconfig/v2/apps")
 	if err != nil {
@@ -88,10 +194,13 @@ func (c *classicClient) InstallApp(stack, token, packageFileName string, package
 
 // InstallApp installs an app on a victoria stack
 func (c *victoriaClient) InstallApp(stack, token, packageFileName string, packageReader io.Reader) error {
-	resp, err := c.resty.R().SetHeader("Content-Type", "application/x-www-form-urlencoded").
+	body, err := c.packageBody(packageFileName, packageReader)
+	if err != nil {
+		return err
+	}
+	resp, err := upload.Stream(c.resty.R(), body).SetHeader("Content-Type", "application/gzip").
 		SetHeader("X-Splunk-Authorization", token).
 		SetHeader("ACS-Legal-Ack", "Y").
-		SetBody(packageReader).
 		Post("/" + stack + "/adminconfig/v2/apps/victoria")
 	if err != nil {
 		return fmt.Errorf("error while installing app: %s", err)
@@ -104,10 +213,17 @@ func (c *victoriaClient) InstallApp(stack, token, packageFileName string, packag
 
 // App ...
 type App struct {
-	Label   *string `json:"label,omitempty"`
-	Package *string `json:"package,omitempty"`
-	Status  string  `json:"status"`
-	Version *string `json:"version,omitempty"`
+	Name         *string `json:"name,omitempty"`
+	Label        *string `json:"label,omitempty"`
+	Package      *string `json:"package,omitempty"`
+	Status       string  `json:"status"`
+	Version      *string `json:"version,omitempty"`
+	SplunkbaseID *string `json:"splunkbaseID,omitempty"`
+}
+
+// IsPrivate reports whether the app is a private app, i.e. was not installed from splunkbase
+func (a *App) IsPrivate() bool {
+	return a.SplunkbaseID == nil || *a.SplunkbaseID == ""
 }
 
 // ListApps on a classic stack
@@ -0,0 +1,245 @@
+package acs_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acstest"
+	"github.com/stretchr/testify/assert"
+)
+
+const testStack = "test-stack"
+
+func init() {
+	acs.SetRetryWaitTime(time.Millisecond)
+}
+
+func appPackage(t *testing.T, name, label, version string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	conf := []byte("[ui]\nlabel = " + label + "\n\n[launcher]\nversion = " + version + "\n")
+	err := tw.WriteHeader(&tar.Header{Name: name + "/default/app.conf", Mode: 0644, Size: int64(len(conf))})
+	assert.Nil(t, err)
+	_, err = tw.Write(conf)
+	assert.Nil(t, err)
+	assert.Nil(t, tw.Close())
+	assert.Nil(t, gz.Close())
+	return buf.Bytes()
+}
+
+func testAppLifecycle(t *testing.T, newServer func() (*acstest.Server, *httptest.Server),
+	newClient func(acsURL, token string, opts ...acs.Option) acs.Client) {
+	assert := assert.New(t)
+	fake, srv := newServer()
+	defer srv.Close()
+	fake.SetToken("stack-token")
+	cli := newClient(srv.URL, "stack-token")
+
+	err := cli.InstallApp(testStack, "splunkbase-token", "testapp.tar.gz",
+		bytes.NewReader(appPackage(t, "testapp", "Test App", "1.0.0")))
+	assert.Nil(err)
+
+	apps, err := cli.ListApps(testStack)
+	assert.Nil(err)
+	assert.Len(apps, 1)
+	assert.Equal("testapp", *apps[0].Name)
+	assert.Equal("Test App", *apps[0].Label)
+	assert.Equal("1.0.0", *apps[0].Version)
+	assert.True(apps[0].IsPrivate())
+
+	app, err := cli.DescribeApp(testStack, "testapp")
+	assert.Nil(err)
+	assert.Equal("1.0.0", *app.Version)
+
+	assert.Nil(cli.UninstallApp(testStack, "testapp"))
+	assert.Empty(fake.Apps(testStack))
+
+	_, err = cli.DescribeApp(testStack, "testapp")
+	assert.Error(err)
+	assert.Contains(err.Error(), "404-object-not-found")
+}
+
+func TestClassicAppLifecycle(t *testing.T) {
+	testAppLifecycle(t, acstest.NewServer, acs.NewClassicWithURL)
+}
+
+func TestVictoriaAppLifecycle(t *testing.T) {
+	testAppLifecycle(t, acstest.NewVictoriaServer, acs.NewVictoriaWithURL)
+}
+
+func TestInvalidToken(t *testing.T) {
+	fake, srv := acstest.NewServer()
+	defer srv.Close()
+	fake.SetToken("stack-token")
+
+	_, err := acs.NewClassicWithURL(srv.URL, "other-token").ListApps(testStack)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "401-unauthorized")
+}
+
+func TestInvalidPackage(t *testing.T) {
+	_, srv := acstest.NewServer()
+	defer srv.Close()
+
+	err := acs.NewClassicWithURL(srv.URL, "stack-token").
+		InstallApp(testStack, "splunkbase-token", "testapp.tar.gz", bytes.NewReader([]byte("not a package")))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "400-invalid-package")
+}
+
+func TestVictoriaPackageValidation(t *testing.T) {
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	gz.Write([]byte("not a tar archive"))
+	gz.Close()
+
+	for name, tc := range map[string]struct {
+		pkg    []byte
+		opts   []acs.Option
+		reason string
+	}{
+		"empty":    {pkg: nil, reason: "empty"},
+		"not gzip": {pkg: []byte("not a package"), reason: "not gzip compressed"},
+		"not tar":  {pkg: gzipped.Bytes(), reason: "not a tar archive"},
+		"too large": {pkg: appPackage(t, "testapp", "Test App", "1.0.0"), opts: []acs.Option{acs.WithMaxPackageSize(100)},
+			reason: "over the limit of 100 bytes"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			fake, srv := acstest.NewServer()
+			defer srv.Close()
+
+			err := acs.NewVictoriaWithURL(srv.URL, "stack-token", tc.opts...).
+				InstallApp(testStack, "splunkbase-token", "testapp.tar.gz", bytes.NewReader(tc.pkg))
+			var pkgErr *acs.PackageError
+			if assert.True(t, errors.As(err, &pkgErr)) {
+				assert.Equal(t, "testapp.tar.gz", pkgErr.Name)
+				assert.Contains(t, pkgErr.Reason, tc.reason)
+			}
+			assert.Equal(t, 0, fake.Requests())
+		})
+	}
+}
+
+func TestVictoriaPackageUnknownSize(t *testing.T) {
+	assert := assert.New(t)
+	fake, srv := acstest.NewVictoriaServer()
+	defer srv.Close()
+	pkg := appPackage(t, "testapp", "Test App", "1.0.0")
+
+	// the size of the package is only known once it is sent
+	err := acs.NewVictoriaWithURL(srv.URL, "stack-token", acs.WithMaxPackageSize(100)).
+		InstallApp(testStack, "splunkbase-token", "testapp.tar.gz", ioutil.NopCloser(bytes.NewReader(pkg)))
+	assert.Error(err)
+	assert.Contains(err.Error(), "over the limit of 100 bytes")
+	assert.Empty(fake.Apps(testStack))
+
+	err = acs.NewVictoriaWithURL(srv.URL, "stack-token").
+		InstallApp(testStack, "splunkbase-token", "testapp.tar.gz", ioutil.NopCloser(bytes.NewReader(pkg)))
+	assert.Nil(err)
+	assert.Len(fake.Apps(testStack), 1)
+}
+
+func TestRetry(t *testing.T) {
+	assert := assert.New(t)
+	fake, srv := acstest.NewServer()
+	defer srv.Close()
+	fake.SetToken("stack-token")
+	cli := acs.NewClassicWithURL(srv.URL, "stack-token")
+
+	// idempotent raw requests are retried
+	fake.Fail(acstest.Failure{Method: http.MethodGet, Status: http.StatusServiceUnavailable,
+		Code: "503-service-unavailable", Description: "try again", Times: 2})
+	_, err := cli.Request(testStack, http.MethodGet, "/adminconfig/v2/apps", nil)
+	assert.Nil(err)
+	assert.Equal(3, fake.Requests())
+
+	// throttled ones too
+	fake.Fail(acstest.Failure{Method: http.MethodGet, Status: http.StatusTooManyRequests,
+		Code: "429-too-many-requests", Description: "slow down", Times: 1})
+	_, err = cli.Request(testStack, http.MethodGet, "/adminconfig/v2/apps", nil)
+	assert.Nil(err)
+	assert.Equal(5, fake.Requests())
+
+	// other throttled requests are not, ACS may have applied them
+	fake.Fail(acstest.Failure{Method: http.MethodPost, Status: http.StatusTooManyRequests,
+		Code: "429-too-many-requests", Description: "slow down", Times: 1})
+	_, err = cli.Request(testStack, http.MethodPost, "/adminconfig/v2/indexes", []byte(`{"name":"web"}`))
+	assert.Error(err)
+	assert.Contains(err.Error(), "429-too-many-requests")
+	assert.Equal(6, fake.Requests())
+
+	// and the requests of the other methods never are
+	fake.Fail(acstest.Failure{Method: http.MethodGet, Status: http.StatusServiceUnavailable,
+		Code: "503-service-unavailable", Description: "try again", Times: 1})
+	_, err = cli.ListApps(testStack)
+	assert.Error(err)
+	assert.Contains(err.Error(), "503-service-unavailable")
+	assert.Equal(7, fake.Requests())
+}
+
+func TestLatency(t *testing.T) {
+	fake, srv := acstest.NewServer()
+	defer srv.Close()
+	fake.SetLatency(50 * time.Millisecond)
+
+	start := time.Now()
+	_, err := acs.NewClassicWithURL(srv.URL, "stack-token").ListApps(testStack)
+	assert.Nil(t, err)
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+}
+
+func TestListIndexesPages(t *testing.T) {
+	assert := assert.New(t)
+	var offsets []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		offsets = append(offsets, r.URL.Query().Get("offset")+"/"+r.URL.Query().Get("count"))
+		page := []acs.Index{}
+		for i := offset; i < 35 && i < offset+30; i++ {
+			page = append(page, acs.Index{Name: fmt.Sprintf("index-%d", i)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer srv.Close()
+
+	indexes, err := acs.NewClassicWithURL(srv.URL, "stack-token").ListIndexes(testStack)
+	assert.Nil(err)
+	assert.Len(indexes, 35)
+	assert.Equal("index-34", indexes[34].Name)
+	assert.Equal([]string{"0/30", "30/30"}, offsets)
+}
+
+func TestCreateExistingIndex(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	}))
+	defer srv.Close()
+
+	err := acs.NewClassicWithURL(srv.URL, "stack-token").CreateIndex(testStack, acs.Index{Name: "web"})
+	assert.True(t, errors.Is(err, acs.ErrIndexExists))
+	assert.EqualError(t, err, "error while creating index: index already exists: web")
+}
+
+func TestWaitForRestartCanceled(t *testing.T) {
+	_, srv := acstest.NewServer()
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := acs.NewClassicWithURL(srv.URL, "stack-token").WaitForRestart(ctx, testStack, time.Hour)
+	assert.EqualError(t, err, "error while waiting for stack restart: context canceled")
+}
@@ -0,0 +1,9 @@
+package acs
+
+import "time"
+
+// SetRetryWaitTime shortens the wait between retries of the clients created afterwards
+func SetRetryWaitTime(d time.Duration) {
+	retryWaitTime = d
+	retryMaxWaitTime = d
+}
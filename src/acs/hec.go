@@ -0,0 +1,124 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acs
+
+import (
+	"fmt"
+)
+
+// HECTokenSpec is the configuration of an http event collector token
+type HECTokenSpec struct {
+	Name              string   `json:"name,omitempty"`
+	DefaultIndex      string   `json:"defaultIndex,omitempty"`
+	AllowedIndexes    []string `json:"allowedIndexes,omitempty"`
+	DefaultSourcetype string   `json:"defaultSourcetype,omitempty"`
+	DefaultSource     string   `json:"defaultSource,omitempty"`
+	DefaultHost       string   `json:"defaultHost,omitempty"`
+	Disabled          bool     `json:"disabled"`
+	UseAck            bool     `json:"useAck"`
+}
+
+// HECToken is an http event collector token along with its configuration
+type HECToken struct {
+	Spec  HECTokenSpec `json:"spec"`
+	Token string       `json:"token,omitempty"`
+}
+
+// CreateHECToken creates an http event collector token on the stack, the returned token holds the
+// generated token value
+func (c *client) CreateHECToken(stack string, spec HECTokenSpec) (*HECToken, error) {
+	type createHECTokenResponse struct {
+		HECToken HECToken `json:"http-event-collector"`
+	}
+	resp, err := c.resty.R().SetBody(spec).SetResult(&createHECTokenResponse{}).
+		Post(fmt.Sprintf("/%s/adminconfig/v2/inputs/http-event-collectors", stack))
+	if err != nil {
+		return nil, fmt.Errorf("error while creating hec token: %s", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("error while creating hec token: %s: %s", resp.Status(), resp.String())
+	}
+	token, ok := resp.Result().(*createHECTokenResponse)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing response")
+	}
+	return &token.HECToken, nil
+}
+
+// ListHECTokens on the stack
+func (c *client) ListHECTokens(stack string) ([]HECToken, error) {
+	type listHECTokensResponse struct {
+		HECTokens []HECToken `json:"http-event-collectors"`
+	}
+	resp, err := c.resty.R().SetResult(&listHECTokensResponse{}).
+		Get(fmt.Sprintf("/%s/adminconfig/v2/inputs/http-event-collectors", stack))
+	if err != nil {
+		return nil, fmt.Errorf("error while listing hec tokens: %s", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("error while listing hec tokens: %s: %s", resp.Status(), resp.String())
+	}
+	tokens, ok := resp.Result().(*listHECTokensResponse)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing response")
+	}
+	return tokens.HECTokens, nil
+}
+
+// DescribeHECToken on the stack
+func (c *client) DescribeHECToken(stack string, tokenName string) (*HECToken, error) {
+	type describeHECTokenResponse struct {
+		HECToken HECToken `json:"http-event-collector"`
+	}
+	resp, err := c.resty.R().SetResult(&describeHECTokenResponse{}).
+		Get(fmt.Sprintf("/%s/adminconfig/v2/inputs/http-event-collectors/%s", stack, tokenName))
+	if err != nil {
+		return nil, fmt.Errorf("error while describing hec token: %s", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("error while describing hec token: %s: %s", resp.Status(), resp.String())
+	}
+	token, ok := resp.Result().(*describeHECTokenResponse)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing response")
+	}
+	return &token.HECToken, nil
+}
+
+// UpdateHECToken replaces the configuration of an http event collector token on the stack
+func (c *client) UpdateHECToken(stack string, tokenName string, spec HECTokenSpec) error {
+	spec.Name = ""
+	resp, err := c.resty.R().SetBody(spec).
+		Put(fmt.Sprintf("/%s/adminconfig/v2/inputs/http-event-collectors/%s", stack, tokenName))
+	if err != nil {
+		return fmt.Errorf("error while updating hec token: %s", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("error while updating hec token: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
+
+// DeleteHECToken from the stack
+func (c *client) DeleteHECToken(stack string, tokenName string) error {
+	resp, err := c.resty.R().Delete(fmt.Sprintf("/%s/adminconfig/v2/inputs/http-event-collectors/%s", stack, tokenName))
+	if err != nil {
+		return fmt.Errorf("error while deleting hec token: %s", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("error while deleting hec token: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
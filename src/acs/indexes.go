@@ -0,0 +1,131 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+const (
+	// IndexTypeEvent is the datatype of indexes storing events
+	IndexTypeEvent = "event"
+	// IndexTypeMetric is the datatype of indexes storing metrics
+	IndexTypeMetric = "metric"
+)
+
+// indexPageSize is the number of indexes ListIndexes asks for per page, which is the default page size of ACS
+const indexPageSize = 30
+
+// ErrIndexExists is returned, wrapped, by CreateIndex when the stack already has an index of the same name
+var ErrIndexExists = errors.New("index already exists")
+
+// IndexSettings are the settings of an index which can be changed after it was created
+type IndexSettings struct {
+	SearchableDays              *int    `json:"searchableDays,omitempty"`
+	MaxDataSizeMB               *int    `json:"maxDataSizeMB,omitempty"`
+	SplunkArchivalRetentionDays *int    `json:"splunkArchivalRetentionDays,omitempty"`
+	SelfStorageBucketPath       *string `json:"selfStorageBucketPath,omitempty"`
+}
+
+// Index ...
+type Index struct {
+	Name     string `json:"name"`
+	Datatype string `json:"datatype,omitempty"`
+	IndexSettings
+	TotalEventCount *string `json:"totalEventCount,omitempty"`
+	TotalRawSizeMB  *string `json:"totalRawSizeMB,omitempty"`
+}
+
+// CreateIndex creates an index on the stack
+func (c *client) CreateIndex(stack string, index Index) error {
+	resp, err := c.resty.R().SetBody(index).Post(fmt.Sprintf("/%s/adminconfig/v2/indexes", stack))
+	if err != nil {
+		return fmt.Errorf("error while creating index: %s", err)
+	}
+	if resp.StatusCode() == http.StatusConflict {
+		return fmt.Errorf("error while creating index: %w: %s", ErrIndexExists, index.Name)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("error while creating index: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
+
+// ListIndexes on the stack, every page of them
+func (c *client) ListIndexes(stack string) ([]Index, error) {
+	indexes := []Index{}
+	for {
+		resp, err := c.resty.R().SetResult(&[]Index{}).
+			SetQueryParams(map[string]string{"offset": strconv.Itoa(len(indexes)), "count": strconv.Itoa(indexPageSize)}).
+			Get(fmt.Sprintf("/%s/adminconfig/v2/indexes", stack))
+		if err != nil {
+			return nil, fmt.Errorf("error while listing indexes: %s", err)
+		}
+		if resp.IsError() {
+			return nil, fmt.Errorf("error while listing indexes: %s: %s", resp.Status(), resp.String())
+		}
+		page, ok := resp.Result().(*[]Index)
+		if !ok {
+			return nil, fmt.Errorf("error while parsing response")
+		}
+		indexes = append(indexes, *page...)
+		if len(*page) < indexPageSize {
+			return indexes, nil
+		}
+	}
+}
+
+// DescribeIndex on the stack
+func (c *client) DescribeIndex(stack string, indexName string) (*Index, error) {
+	resp, err := c.resty.R().SetResult(&Index{}).Get(fmt.Sprintf("/%s/adminconfig/v2/indexes/%s", stack, indexName))
+	if err != nil {
+		return nil, fmt.Errorf("error while describing index: %s", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("error while describing index: %s: %s", resp.Status(), resp.String())
+	}
+	index, ok := resp.Result().(*Index)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing response")
+	}
+	return index, nil
+}
+
+// UpdateIndex changes the settings of an index on the stack, settings left nil are not changed
+func (c *client) UpdateIndex(stack string, indexName string, settings IndexSettings) error {
+	resp, err := c.resty.R().SetBody(settings).Patch(fmt.Sprintf("/%s/adminconfig/v2/indexes/%s", stack, indexName))
+	if err != nil {
+		return fmt.Errorf("error while updating index: %s", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("error while updating index: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
+
+// DeleteIndex from the stack
+func (c *client) DeleteIndex(stack string, indexName string) error {
+	resp, err := c.resty.R().Delete(fmt.Sprintf("/%s/adminconfig/v2/indexes/%s", stack, indexName))
+	if err != nil {
+		return fmt.Errorf("error while deleting index: %s", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("error while deleting index: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
@@ -0,0 +1,352 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acs
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/splunk/acs-privateapps-demo/src/telemetry"
+)
+
+// Instrument records a span named after the method, i.e. acs.InstallApp, around every call to the client
+func Instrument(client Client, tracer *telemetry.Tracer) Client {
+	return &instrumentedClient{Client: client, tracer: tracer}
+}
+
+type instrumentedClient struct {
+	Client
+	tracer *telemetry.Tracer
+}
+
+func (c *instrumentedClient) start(method, stack string, attributes ...interface{}) *telemetry.Span {
+	return c.tracer.Start("acs."+method, append([]interface{}{"stack", stack}, attributes...)...)
+}
+
+func (c *instrumentedClient) InstallApp(stack, token, packageFileName string, packageReader io.Reader) error {
+	span := c.start("InstallApp", stack, "package", packageFileName)
+	err := c.Client.InstallApp(stack, token, packageFileName, packageReader)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) DescribeApp(stack string, appName string) (*App, error) {
+	span := c.start("DescribeApp", stack, "app", appName)
+	result, err := c.Client.DescribeApp(stack, appName)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) ListApps(stack string) ([]App, error) {
+	span := c.start("ListApps", stack)
+	result, err := c.Client.ListApps(stack)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) UninstallApp(stack string, appName string) error {
+	span := c.start("UninstallApp", stack, "app", appName)
+	err := c.Client.UninstallApp(stack, appName)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) InstallSplunkbaseApp(stack, token, splunkbaseID, version, licenseURL string) error {
+	span := c.start("InstallSplunkbaseApp", stack, "splunkbase.id", splunkbaseID, "version", version)
+	err := c.Client.InstallSplunkbaseApp(stack, token, splunkbaseID, version, licenseURL)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) DescribeSplunkbaseApp(stack string, appName string) (*App, error) {
+	span := c.start("DescribeSplunkbaseApp", stack, "app", appName)
+	result, err := c.Client.DescribeSplunkbaseApp(stack, appName)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) ListSplunkbaseApps(stack string) ([]App, error) {
+	span := c.start("ListSplunkbaseApps", stack)
+	result, err := c.Client.ListSplunkbaseApps(stack)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) UpdateSplunkbaseApp(stack, token, appName, version, licenseURL string) error {
+	span := c.start("UpdateSplunkbaseApp", stack, "app", appName, "version", version)
+	err := c.Client.UpdateSplunkbaseApp(stack, token, appName, version, licenseURL)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) UninstallSplunkbaseApp(stack string, appName string) error {
+	span := c.start("UninstallSplunkbaseApp", stack, "app", appName)
+	err := c.Client.UninstallSplunkbaseApp(stack, appName)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) CreateIndex(stack string, index Index) error {
+	span := c.start("CreateIndex", stack, "index", index.Name)
+	err := c.Client.CreateIndex(stack, index)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) DescribeIndex(stack string, indexName string) (*Index, error) {
+	span := c.start("DescribeIndex", stack, "index", indexName)
+	result, err := c.Client.DescribeIndex(stack, indexName)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) ListIndexes(stack string) ([]Index, error) {
+	span := c.start("ListIndexes", stack)
+	result, err := c.Client.ListIndexes(stack)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) UpdateIndex(stack string, indexName string, settings IndexSettings) error {
+	span := c.start("UpdateIndex", stack, "index", indexName)
+	err := c.Client.UpdateIndex(stack, indexName, settings)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) DeleteIndex(stack string, indexName string) error {
+	span := c.start("DeleteIndex", stack, "index", indexName)
+	err := c.Client.DeleteIndex(stack, indexName)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) CreateHECToken(stack string, spec HECTokenSpec) (*HECToken, error) {
+	span := c.start("CreateHECToken", stack, "token.name", spec.Name)
+	result, err := c.Client.CreateHECToken(stack, spec)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) DescribeHECToken(stack string, tokenName string) (*HECToken, error) {
+	span := c.start("DescribeHECToken", stack, "token.name", tokenName)
+	result, err := c.Client.DescribeHECToken(stack, tokenName)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) ListHECTokens(stack string) ([]HECToken, error) {
+	span := c.start("ListHECTokens", stack)
+	result, err := c.Client.ListHECTokens(stack)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) UpdateHECToken(stack string, tokenName string, spec HECTokenSpec) error {
+	span := c.start("UpdateHECToken", stack, "token.name", tokenName)
+	err := c.Client.UpdateHECToken(stack, tokenName, spec)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) DeleteHECToken(stack string, tokenName string) error {
+	span := c.start("DeleteHECToken", stack, "token.name", tokenName)
+	err := c.Client.DeleteHECToken(stack, tokenName)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) ListAllowList(stack, feature string) ([]string, error) {
+	span := c.start("ListAllowList", stack, "feature", feature)
+	result, err := c.Client.ListAllowList(stack, feature)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) AddAllowListSubnets(stack, feature string, subnets []string) error {
+	span := c.start("AddAllowListSubnets", stack, "feature", feature)
+	err := c.Client.AddAllowListSubnets(stack, feature, subnets)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) RemoveAllowListSubnets(stack, feature string, subnets []string) error {
+	span := c.start("RemoveAllowListSubnets", stack, "feature", feature)
+	err := c.Client.RemoveAllowListSubnets(stack, feature, subnets)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) StackStatus(stack string) (*StackStatus, error) {
+	span := c.start("StackStatus", stack)
+	result, err := c.Client.StackStatus(stack)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) RestartRequired(stack string) (bool, error) {
+	span := c.start("RestartRequired", stack)
+	result, err := c.Client.RestartRequired(stack)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) RestartStack(stack string) error {
+	span := c.start("RestartStack", stack)
+	err := c.Client.RestartStack(stack)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) WaitForRestart(ctx context.Context, stack string, timeout time.Duration) error {
+	span := c.start("WaitForRestart", stack)
+	err := c.Client.WaitForRestart(ctx, stack, timeout)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) CreateAuthToken(stack string, request AuthTokenRequest) (*AuthToken, error) {
+	span := c.start("CreateAuthToken", stack)
+	result, err := c.Client.CreateAuthToken(stack, request)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) ListAuthTokens(stack string) ([]AuthToken, error) {
+	span := c.start("ListAuthTokens", stack)
+	result, err := c.Client.ListAuthTokens(stack)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) DeleteAuthToken(stack string, tokenID string) error {
+	span := c.start("DeleteAuthToken", stack, "token.id", tokenID)
+	err := c.Client.DeleteAuthToken(stack, tokenID)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) CreateOutboundPort(stack string, port OutboundPort, reason string) error {
+	span := c.start("CreateOutboundPort", stack, "port", port.Port)
+	err := c.Client.CreateOutboundPort(stack, port, reason)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) DescribeOutboundPort(stack string, port int) (*OutboundPort, error) {
+	span := c.start("DescribeOutboundPort", stack, "port", port)
+	result, err := c.Client.DescribeOutboundPort(stack, port)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) ListOutboundPorts(stack string) ([]OutboundPort, error) {
+	span := c.start("ListOutboundPorts", stack)
+	result, err := c.Client.ListOutboundPorts(stack)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) DeleteOutboundPort(stack string, port int, subnets []string) error {
+	span := c.start("DeleteOutboundPort", stack, "port", port)
+	err := c.Client.DeleteOutboundPort(stack, port, subnets)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) CreateRole(stack string, role Role) error {
+	span := c.start("CreateRole", stack, "role", role.Name)
+	err := c.Client.CreateRole(stack, role)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) DescribeRole(stack string, roleName string) (*Role, error) {
+	span := c.start("DescribeRole", stack, "role", roleName)
+	result, err := c.Client.DescribeRole(stack, roleName)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) ListRoles(stack string) ([]Role, error) {
+	span := c.start("ListRoles", stack)
+	result, err := c.Client.ListRoles(stack)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) UpdateRole(stack string, roleName string, settings RoleSettings) error {
+	span := c.start("UpdateRole", stack, "role", roleName)
+	err := c.Client.UpdateRole(stack, roleName, settings)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) DeleteRole(stack string, roleName string) error {
+	span := c.start("DeleteRole", stack, "role", roleName)
+	err := c.Client.DeleteRole(stack, roleName)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) CreateUser(stack string, user User) error {
+	span := c.start("CreateUser", stack, "user", user.Name)
+	err := c.Client.CreateUser(stack, user)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) DescribeUser(stack string, userName string) (*User, error) {
+	span := c.start("DescribeUser", stack, "user", userName)
+	result, err := c.Client.DescribeUser(stack, userName)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) ListUsers(stack string) ([]User, error) {
+	span := c.start("ListUsers", stack)
+	result, err := c.Client.ListUsers(stack)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) UpdateUser(stack string, userName string, settings UserSettings) error {
+	span := c.start("UpdateUser", stack, "user", userName)
+	err := c.Client.UpdateUser(stack, userName, settings)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) DeleteUser(stack string, userName string) error {
+	span := c.start("DeleteUser", stack, "user", userName)
+	err := c.Client.DeleteUser(stack, userName)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) ListMaintenanceWindows(stack string) ([]MaintenanceWindow, error) {
+	span := c.start("ListMaintenanceWindows", stack)
+	result, err := c.Client.ListMaintenanceWindows(stack)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) Request(stack, method, path string, body []byte) (*RawResponse, error) {
+	span := c.start("Request", stack, "method", method, "path", path)
+	result, err := c.Client.Request(stack, method, path, body)
+	span.Finish(err)
+	return result, err
+}
@@ -0,0 +1,110 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acs
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TokenClaims are the claims of a stack JWT token
+type TokenClaims struct {
+	Issuer           string   `json:"iss,omitempty"`
+	Subject          string   `json:"sub,omitempty"`
+	Audience         Audience `json:"aud,omitempty"`
+	IdentityProvider string   `json:"idp,omitempty"`
+	ID               string   `json:"jti,omitempty"`
+	IssuedAt         int64    `json:"iat,omitempty"`
+	NotBefore        int64    `json:"nbf,omitempty"`
+	ExpiresAt        int64    `json:"exp,omitempty"`
+}
+
+// Audience of a JWT token, which may be given as a single string or a list of strings
+type Audience []string
+
+// UnmarshalJSON ...
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// ParseTokenClaims decodes the claims of a JWT token. The signature is not verified, the claims are only
+// used to catch unusable tokens before calling ACS.
+func ParseTokenClaims(token string) (*TokenClaims, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid token: not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %s", err)
+	}
+	claims := &TokenClaims{}
+	if err = json.Unmarshal(payload, claims); err != nil {
+		return nil, fmt.Errorf("invalid token: %s", err)
+	}
+	return claims, nil
+}
+
+// Expiry returns when the token expires, the zero time if it never does
+func (c *TokenClaims) Expiry() time.Time {
+	if c.ExpiresAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(c.ExpiresAt, 0)
+}
+
+// ExpiresWithin reports whether the token is expired at now+d
+func (c *TokenClaims) ExpiresWithin(now time.Time, d time.Duration) bool {
+	return c.ExpiresAt != 0 && !now.Add(d).Before(c.Expiry())
+}
+
+// Check returns an error if the token is expired or not yet valid at now
+func (c *TokenClaims) Check(now time.Time) error {
+	if c.ExpiresWithin(now, 0) {
+		return fmt.Errorf("token expired at %s", c.Expiry().Format(time.RFC3339))
+	}
+	if c.NotBefore != 0 && now.Before(time.Unix(c.NotBefore, 0)) {
+		return fmt.Errorf("token is not valid before %s", time.Unix(c.NotBefore, 0).Format(time.RFC3339))
+	}
+	return nil
+}
+
+// IssuedFor reports whether the token could have been issued by the stack. Splunk sets the issuer to
+// "<user> from <host>", so a token whose fully qualified issuing host does not mention the stack was created
+// on another stack. Tokens with an unqualified or unknown issuer are assumed to match.
+func (c *TokenClaims) IssuedFor(stack string) bool {
+	i := strings.LastIndex(c.Issuer, " from ")
+	if i < 0 {
+		return true
+	}
+	host := c.Issuer[i+len(" from "):]
+	if !strings.Contains(host, ".") {
+		return true
+	}
+	return strings.Contains(strings.ToLower(host), strings.ToLower(stack))
+}
@@ -0,0 +1,51 @@
+package acs
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testToken(payload string) string {
+	return "eyJhbGciOiJIUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".c2ln"
+}
+
+func TestParseTokenClaims(t *testing.T) {
+	assert := assert.New(t)
+
+	claims, err := ParseTokenClaims(testToken(`{"iss":"sc_admin from sh-i-1.foo.splunkcloud.com","sub":"sc_admin","aud":"acs","exp":1700000000}`))
+	assert.Nil(err)
+	assert.Equal("sc_admin", claims.Subject)
+	assert.Equal(Audience{"acs"}, claims.Audience)
+	assert.Equal(time.Unix(1700000000, 0), claims.Expiry())
+	assert.True(claims.IssuedFor("foo"))
+	assert.False(claims.IssuedFor("bar"))
+
+	claims, err = ParseTokenClaims(testToken(`{"iss":"sc_admin from sh-i-1","aud":["a","b"]}`))
+	assert.Nil(err)
+	assert.Equal(Audience{"a", "b"}, claims.Audience)
+	assert.True(claims.Expiry().IsZero())
+	assert.True(claims.IssuedFor("bar"))
+
+	_, err = ParseTokenClaims("foo")
+	assert.Error(err)
+	_, err = ParseTokenClaims("a.!!!.c")
+	assert.Error(err)
+}
+
+func TestTokenClaimsCheck(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Unix(1700000000, 0)
+
+	claims := &TokenClaims{ExpiresAt: now.Add(time.Hour).Unix()}
+	assert.Nil(claims.Check(now))
+	assert.False(claims.ExpiresWithin(now, 30*time.Minute))
+	assert.True(claims.ExpiresWithin(now, 2*time.Hour))
+	assert.Error(claims.Check(now.Add(2 * time.Hour)))
+
+	claims = &TokenClaims{NotBefore: now.Add(time.Hour).Unix()}
+	assert.Error(claims.Check(now))
+	assert.Nil(claims.Check(now.Add(2 * time.Hour)))
+}
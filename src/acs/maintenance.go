@@ -0,0 +1,66 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acs
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaintenanceWindow is a scheduled maintenance of the stack
+type MaintenanceWindow struct {
+	ID        string    `json:"id,omitempty"`
+	Type      string    `json:"type,omitempty"`
+	Status    string    `json:"status,omitempty"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Comment   string    `json:"comment,omitempty"`
+}
+
+// Contains reports whether t falls within the maintenance window
+func (w *MaintenanceWindow) Contains(t time.Time) bool {
+	return !t.Before(w.StartTime) && t.Before(w.EndTime)
+}
+
+// ActiveMaintenanceWindow returns the window t falls within, the one ending last if they overlap, or nil
+func ActiveMaintenanceWindow(windows []MaintenanceWindow, t time.Time) *MaintenanceWindow {
+	var active *MaintenanceWindow
+	for i := range windows {
+		if windows[i].Contains(t) && (active == nil || windows[i].EndTime.After(active.EndTime)) {
+			active = &windows[i]
+		}
+	}
+	return active
+}
+
+// ListMaintenanceWindows scheduled on the stack
+func (c *client) ListMaintenanceWindows(stack string) ([]MaintenanceWindow, error) {
+	type listMaintenanceWindowsResponse struct {
+		Schedules []MaintenanceWindow `json:"schedules"`
+	}
+	resp, err := c.resty.R().SetResult(&listMaintenanceWindowsResponse{}).
+		Get(fmt.Sprintf("/%s/adminconfig/v2/maintenance-windows/schedules", stack))
+	if err != nil {
+		return nil, fmt.Errorf("error while listing maintenance windows: %s", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("error while listing maintenance windows: %s: %s", resp.Status(), resp.String())
+	}
+	windows, ok := resp.Result().(*listMaintenanceWindowsResponse)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing response")
+	}
+	return windows.Schedules, nil
+}
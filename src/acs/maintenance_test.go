@@ -0,0 +1,25 @@
+package acs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActiveMaintenanceWindow(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	windows := []MaintenanceWindow{
+		{ID: "past", StartTime: now.Add(-3 * time.Hour), EndTime: now.Add(-time.Hour)},
+		{ID: "short", StartTime: now.Add(-time.Hour), EndTime: now.Add(time.Hour)},
+		{ID: "long", StartTime: now.Add(-time.Hour), EndTime: now.Add(2 * time.Hour)},
+		{ID: "future", StartTime: now.Add(time.Hour), EndTime: now.Add(3 * time.Hour)},
+	}
+	assert.Equal("long", ActiveMaintenanceWindow(windows, now).ID)
+	assert.Equal("future", ActiveMaintenanceWindow(windows, now.Add(150*time.Minute)).ID)
+	assert.Nil(ActiveMaintenanceWindow(windows, now.Add(-4*time.Hour)))
+	// the end of a window is exclusive
+	assert.Nil(ActiveMaintenanceWindow(windows[:1], now.Add(-time.Hour)))
+}
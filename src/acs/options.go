@@ -0,0 +1,43 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acs
+
+import (
+	"github.com/splunk/acs-privateapps-demo/src/httpclient"
+)
+
+// Option configures a Client
+type Option func(*options)
+
+type options struct {
+	http           httpclient.Config
+	maxPackageSize int64
+}
+
+// WithHTTP sets up the http client of the client, i.e. its transport, proxy and hooks
+func WithHTTP(opts ...httpclient.Option) Option {
+	return func(o *options) {
+		for _, opt := range opts {
+			opt(&o.http)
+		}
+	}
+}
+
+// WithMaxPackageSize sets the largest app package the client uploads to victoria stacks, 0 lifts the limit
+func WithMaxPackageSize(size int64) Option {
+	return func(o *options) {
+		o.maxPackageSize = size
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acs
+
+import (
+	"fmt"
+)
+
+// OutboundPort is a port the stack is allowed to connect out to on the given subnets
+type OutboundPort struct {
+	Port    int      `json:"port"`
+	Subnets []string `json:"subnets"`
+}
+
+// ListOutboundPorts on the stack
+func (c *client) ListOutboundPorts(stack string) ([]OutboundPort, error) {
+	type listOutboundPortsResponse struct {
+		OutboundPorts []OutboundPort `json:"outboundPorts"`
+	}
+	resp, err := c.resty.R().SetResult(&listOutboundPortsResponse{}).
+		Get(fmt.Sprintf("/%s/adminconfig/v2/access/outbound-ports", stack))
+	if err != nil {
+		return nil, fmt.Errorf("error while listing outbound ports: %s", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("error while listing outbound ports: %s: %s", resp.Status(), resp.String())
+	}
+	ports, ok := resp.Result().(*listOutboundPortsResponse)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing response")
+	}
+	return ports.OutboundPorts, nil
+}
+
+// DescribeOutboundPort on the stack
+func (c *client) DescribeOutboundPort(stack string, port int) (*OutboundPort, error) {
+	resp, err := c.resty.R().SetResult(&OutboundPort{}).
+		Get(fmt.Sprintf("/%s/adminconfig/v2/access/outbound-ports/%d", stack, port))
+	if err != nil {
+		return nil, fmt.Errorf("error while describing outbound port: %s", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("error while describing outbound port: %s: %s", resp.Status(), resp.String())
+	}
+	outboundPort, ok := resp.Result().(*OutboundPort)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing response")
+	}
+	return outboundPort, nil
+}
+
+// CreateOutboundPort opens the port for outbound connections from the stack to the subnets, the reason is
+// recorded along with the change
+func (c *client) CreateOutboundPort(stack string, port OutboundPort, reason string) error {
+	if err := validateSubnetChange(port.Subnets); err != nil {
+		return err
+	}
+	type createOutboundPortRequest struct {
+		OutboundPorts []OutboundPort `json:"outboundPorts"`
+		Reason        string         `json:"reason,omitempty"`
+	}
+	resp, err := c.resty.R().SetBody(createOutboundPortRequest{OutboundPorts: []OutboundPort{port}, Reason: reason}).
+		Post(fmt.Sprintf("/%s/adminconfig/v2/access/outbound-ports", stack))
+	if err != nil {
+		return fmt.Errorf("error while creating outbound port: %s", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("error while creating outbound port: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
+
+// DeleteOutboundPort closes the port for outbound connections from the stack to the subnets
+func (c *client) DeleteOutboundPort(stack string, port int, subnets []string) error {
+	if err := validateSubnetChange(subnets); err != nil {
+		return err
+	}
+	resp, err := c.resty.R().SetBody(map[string][]string{"subnets": subnets}).
+		Delete(fmt.Sprintf("/%s/adminconfig/v2/access/outbound-ports/%d", stack, port))
+	if err != nil {
+		return fmt.Errorf("error while deleting outbound port: %s", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("error while deleting outbound port: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acs
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/splunk/acs-privateapps-demo/src/upload"
+)
+
+// DefaultMaxPackageSize is the largest app package uploaded to victoria stacks, see WithMaxPackageSize
+const DefaultMaxPackageSize = 128 << 20
+
+// sniffSize is how much of a package is buffered to check that it is a gzipped tar
+const sniffSize = 64 << 10
+
+const tarBlockSize = 512
+
+// PackageError is returned when an app package is rejected before it is uploaded
+type PackageError struct {
+	Name   string
+	Reason string
+}
+
+func (e *PackageError) Error() string {
+	return fmt.Sprintf("invalid app package %s: %s", e.Name, e.Reason)
+}
+
+// packageBody checks that the package is a gzipped tar within the size limit and returns the body streaming
+// it. Packages of unknown size are cut off once they go over the limit.
+func (c *client) packageBody(name string, packageReader io.Reader) (*upload.Body, error) {
+	size := upload.Size(packageReader)
+	if c.maxPackageSize > 0 && size > c.maxPackageSize {
+		return nil, &PackageError{Name: name, Reason: fmt.Sprintf("it is %d bytes, over the limit of %d bytes",
+			size, c.maxPackageSize)}
+	}
+	r := bufio.NewReaderSize(packageReader, sniffSize)
+	head, err := r.Peek(sniffSize)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("error while reading app package: %s", err)
+	}
+	if reason := sniffPackage(head, len(head) < sniffSize); reason != "" {
+		return nil, &PackageError{Name: name, Reason: reason}
+	}
+	var body io.Reader = r
+	if size < 0 && c.maxPackageSize > 0 {
+		body = &maxSizeReader{reader: r, name: name, max: c.maxPackageSize}
+	}
+	return upload.NewSizedBody(body, size), nil
+}
+
+// sniffPackage returns why the package starting with head is not a gzipped tar, or an empty string when it
+// could be one. complete tells whether head is the whole package.
+func sniffPackage(head []byte, complete bool) string {
+	if len(head) == 0 {
+		return "it is empty"
+	}
+	if len(head) < 2 || head[0] != 0x1f || head[1] != 0x8b {
+		return "it is not gzip compressed"
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(head))
+	if err != nil {
+		return fmt.Sprintf("it is not gzip compressed: %s", err)
+	}
+	block := make([]byte, tarBlockSize)
+	if _, err = io.ReadFull(gz, block); err != nil {
+		if complete {
+			return "it is not a tar archive"
+		}
+		// the first block did not fit in head, the service will tell
+		return ""
+	}
+	if bytes.Equal(block, make([]byte, tarBlockSize)) {
+		return "it is an empty tar archive"
+	}
+	if !validTarHeader(block) {
+		return "it is not a tar archive"
+	}
+	return ""
+}
+
+// validTarHeader checks the checksum of a tar header block, which every tar format has
+func validTarHeader(block []byte) bool {
+	field := strings.Trim(string(block[148:156]), " \x00")
+	expected, err := strconv.ParseInt(field, 8, 64)
+	if err != nil {
+		return false
+	}
+	var sum int64
+	for i, b := range block {
+		if i >= 148 && i < 156 {
+			b = ' '
+		}
+		sum += int64(b)
+	}
+	return sum == expected
+}
+
+// maxSizeReader fails once more than max bytes were read
+type maxSizeReader struct {
+	reader io.Reader
+	name   string
+	max    int64
+	read   int64
+}
+
+func (m *maxSizeReader) Read(p []byte) (int, error) {
+	n, err := m.reader.Read(p)
+	m.read += int64(n)
+	if m.read > m.max {
+		return n, &PackageError{Name: m.name, Reason: fmt.Sprintf("it is over the limit of %d bytes", m.max)}
+	}
+	return n, err
+}
@@ -0,0 +1,69 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acs
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// RawResponse is the undecoded response of an ACS endpoint
+type RawResponse struct {
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Body       []byte
+}
+
+// Request calls an arbitrary ACS endpoint of the stack, it's an escape hatch for the endpoints this client
+// does not cover yet. The path is relative to the stack, e.g. "/adminconfig/v2/status", and the body, if
+// any, is sent as JSON. Idempotent requests are retried, see Client. The response is returned along with the
+// error when ACS responds with an error.
+func (c *client) Request(stack, method, path string, body []byte) (*RawResponse, error) {
+	path = stackPath(stack, path)
+	request := c.raw.R()
+	if len(body) > 0 {
+		request.SetHeader("Content-Type", "application/json").SetBody(body)
+	}
+	resp, err := request.Execute(strings.ToUpper(method), path)
+	if err != nil {
+		return nil, fmt.Errorf("error while calling %s %s: %s", method, path, err)
+	}
+	raw := &RawResponse{
+		StatusCode: resp.StatusCode(),
+		Status:     resp.Status(),
+		Header:     resp.Header(),
+		Body:       resp.Body(),
+	}
+	if resp.IsError() {
+		if e, ok := resp.Error().(*acsError); ok && e.Code != "" {
+			return raw, fmt.Errorf("error while calling %s %s: %s: %s", method, path, resp.Status(), e)
+		}
+		return raw, fmt.Errorf("error while calling %s %s: %s: %s", method, path, resp.Status(), resp.String())
+	}
+	return raw, nil
+}
+
+// stackPath prefixes the path with the stack unless it already starts with it, i.e. "/<stack>" alone or
+// followed by "/" or "?"
+func stackPath(stack, path string) string {
+	path = "/" + strings.TrimPrefix(path, "/")
+	rest := strings.TrimPrefix(path, "/"+stack)
+	if rest != path && (rest == "" || rest[0] == '/' || rest[0] == '?') {
+		return path
+	}
+	return "/" + stack + path
+}
@@ -0,0 +1,25 @@
+package acs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStackPath(t *testing.T) {
+	for _, test := range []struct {
+		path string
+		want string
+	}{
+		{"/adminconfig/v2/status", "/test-stack/adminconfig/v2/status"},
+		{"adminconfig/v2/status", "/test-stack/adminconfig/v2/status"},
+		{"/test-stack/adminconfig/v2/status", "/test-stack/adminconfig/v2/status"},
+		{"test-stack/adminconfig/v2/status", "/test-stack/adminconfig/v2/status"},
+		{"/test-stack", "/test-stack"},
+		{"/test-stack?count=1", "/test-stack?count=1"},
+		{"/test-stack-2/adminconfig/v2/status", "/test-stack/test-stack-2/adminconfig/v2/status"},
+		{"/", "/test-stack/"},
+	} {
+		assert.Equal(t, test.want, stackPath("test-stack", test.path), test.path)
+	}
+}
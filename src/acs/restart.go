@@ -0,0 +1,74 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acs
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// restartPollInterval is how often WaitForRestart checks on the stack
+var restartPollInterval = 30 * time.Second
+
+// RestartRequired reports whether the stack needs a restart for changes to take effect
+func (c *client) RestartRequired(stack string) (bool, error) {
+	status, err := c.StackStatus(stack)
+	if err != nil {
+		return false, err
+	}
+	return status.Messages.RestartRequired, nil
+}
+
+// RestartStack triggers a restart of the stack, use WaitForRestart to wait for it to finish
+func (c *client) RestartStack(stack string) error {
+	resp, err := c.resty.R().Post(fmt.Sprintf("/%s/adminconfig/v2/restart-now", stack))
+	if err != nil {
+		return fmt.Errorf("error while restarting stack: %s", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("error while restarting stack: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
+
+// WaitForRestart polls the stack until it is ready and no longer requires a restart. Errors while polling
+// are expected as the stack goes down and are only returned if the timeout expires. The wait stops when ctx
+// is done.
+func (c *client) WaitForRestart(ctx context.Context, stack string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	var lastErr error
+	for {
+		timer := time.NewTimer(restartPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("error while waiting for stack restart: %s", ctx.Err())
+		case <-timer.C:
+		}
+		status, err := c.StackStatus(stack)
+		if err == nil && status.IsReady() && !status.Messages.RestartRequired {
+			return nil
+		}
+		if err != nil {
+			lastErr = err
+		} else {
+			lastErr = fmt.Errorf("stack status is '%s'", status.Infrastructure.StackStatus)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for stack restart: %s", lastErr)
+		}
+	}
+}
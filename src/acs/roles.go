@@ -0,0 +1,109 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acs
+
+import (
+	"fmt"
+)
+
+// RoleSettings are the settings of a role which can be changed after it was created
+type RoleSettings struct {
+	Capabilities       []string `json:"capabilities,omitempty"`
+	ImportedRoles      []string `json:"importedRoles,omitempty"`
+	SrchIndexesAllowed []string `json:"srchIndexesAllowed,omitempty"`
+	SrchIndexesDefault []string `json:"srchIndexesDefault,omitempty"`
+	SrchJobsQuota      *int     `json:"srchJobsQuota,omitempty"`
+	RtSrchJobsQuota    *int     `json:"rtSrchJobsQuota,omitempty"`
+	SrchDiskQuota      *int     `json:"srchDiskQuota,omitempty"`
+	SrchTimeWin        *int     `json:"srchTimeWin,omitempty"`
+	DefaultApp         string   `json:"defaultApp,omitempty"`
+}
+
+// Role ...
+type Role struct {
+	Name string `json:"name"`
+	RoleSettings
+}
+
+// CreateRole creates a role on the stack
+func (c *client) CreateRole(stack string, role Role) error {
+	resp, err := c.resty.R().SetBody(role).Post(fmt.Sprintf("/%s/adminconfig/v2/roles", stack))
+	if err != nil {
+		return fmt.Errorf("error while creating role: %s", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("error while creating role: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
+
+// ListRoles on the stack
+func (c *client) ListRoles(stack string) ([]Role, error) {
+	type listRolesResponse struct {
+		Roles []Role `json:"roles"`
+	}
+	resp, err := c.resty.R().SetResult(&listRolesResponse{}).Get(fmt.Sprintf("/%s/adminconfig/v2/roles", stack))
+	if err != nil {
+		return nil, fmt.Errorf("error while listing roles: %s", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("error while listing roles: %s: %s", resp.Status(), resp.String())
+	}
+	roles, ok := resp.Result().(*listRolesResponse)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing response")
+	}
+	return roles.Roles, nil
+}
+
+// DescribeRole on the stack
+func (c *client) DescribeRole(stack string, roleName string) (*Role, error) {
+	resp, err := c.resty.R().SetResult(&Role{}).Get(fmt.Sprintf("/%s/adminconfig/v2/roles/%s", stack, roleName))
+	if err != nil {
+		return nil, fmt.Errorf("error while describing role: %s", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("error while describing role: %s: %s", resp.Status(), resp.String())
+	}
+	role, ok := resp.Result().(*Role)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing response")
+	}
+	return role, nil
+}
+
+// UpdateRole changes the settings of a role on the stack, settings left empty are not changed
+func (c *client) UpdateRole(stack string, roleName string, settings RoleSettings) error {
+	resp, err := c.resty.R().SetBody(settings).Patch(fmt.Sprintf("/%s/adminconfig/v2/roles/%s", stack, roleName))
+	if err != nil {
+		return fmt.Errorf("error while updating role: %s", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("error while updating role: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
+
+// DeleteRole from the stack
+func (c *client) DeleteRole(stack string, roleName string) error {
+	resp, err := c.resty.R().Delete(fmt.Sprintf("/%s/adminconfig/v2/roles/%s", stack, roleName))
+	if err != nil {
+		return fmt.Errorf("error while deleting role: %s", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("error while deleting role: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
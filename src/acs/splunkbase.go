@@ -0,0 +1,113 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acs
+
+import (
+	"fmt"
+)
+
+// InstallSplunkbaseApp installs a splunkbase app on a classic stack, the latest compatible version is
+// installed when version is empty
+func (c *classicClient) InstallSplunkbaseApp(stack, token, splunkbaseID, version, licenseURL string) error {
+	return installSplunkbaseApp(c.client, fmt.Sprintf("/%s/adminconfig/v2/apps?splunkbase=true", stack),
+		token, splunkbaseID, version, licenseURL)
+}
+
+// InstallSplunkbaseApp installs a splunkbase app on a victoria stack, the latest compatible version is
+// installed when version is empty
+func (c *victoriaClient) InstallSplunkbaseApp(stack, token, splunkbaseID, version, licenseURL string) error {
+	return installSplunkbaseApp(c.client, fmt.Sprintf("/%s/adminconfig/v2/apps/victoria?splunkbase=true", stack),
+		token, splunkbaseID, version, licenseURL)
+}
+
+func installSplunkbaseApp(c client, url, token, splunkbaseID, version, licenseURL string) error {
+	formData := map[string]string{"splunkbaseID": splunkbaseID}
+	if version != "" {
+		formData["version"] = version
+	}
+	resp, err := c.resty.R().SetFormData(formData).
+		SetHeader("X-Splunkbase-Authorization", token).
+		SetHeader("ACS-Licensing-Ack", licenseURL).
+		Post(url)
+	if err != nil {
+		return fmt.Errorf("error while installing splunkbase app: %s", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("error while installing splunkbase app: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
+
+// ListSplunkbaseApps on a classic stack
+func (c *classicClient) ListSplunkbaseApps(stack string) ([]App, error) {
+	return listApps(c.client, fmt.Sprintf("/%s/adminconfig/v2/apps?splunkbase=true", stack))
+}
+
+// ListSplunkbaseApps on a victoria stack
+func (c *victoriaClient) ListSplunkbaseApps(stack string) ([]App, error) {
+	return listApps(c.client, fmt.Sprintf("/%s/adminconfig/v2/apps/victoria?splunkbase=true", stack))
+}
+
+// DescribeSplunkbaseApp on a classic stack
+func (c *classicClient) DescribeSplunkbaseApp(stack string, appName string) (*App, error) {
+	return describeApp(c.client, fmt.Sprintf("/%s/adminconfig/v2/apps/%s?splunkbase=true", stack, appName))
+}
+
+// DescribeSplunkbaseApp on a victoria stack
+func (c *victoriaClient) DescribeSplunkbaseApp(stack string, appName string) (*App, error) {
+	return describeApp(c.client, fmt.Sprintf("/%s/adminconfig/v2/apps/victoria/%s?splunkbase=true", stack, appName))
+}
+
+// UpdateSplunkbaseApp updates a splunkbase app on a classic stack, the latest compatible version is
+// installed when version is empty
+func (c *classicClient) UpdateSplunkbaseApp(stack, token, appName, version, licenseURL string) error {
+	return updateSplunkbaseApp(c.client, fmt.Sprintf("/%s/adminconfig/v2/apps/%s?splunkbase=true", stack, appName),
+		token, version, licenseURL)
+}
+
+// UpdateSplunkbaseApp updates a splunkbase app on a victoria stack, the latest compatible version is
+// installed when version is empty
+func (c *victoriaClient) UpdateSplunkbaseApp(stack, token, appName, version, licenseURL string) error {
+	return updateSplunkbaseApp(c.client, fmt.Sprintf("/%s/adminconfig/v2/apps/victoria/%s?splunkbase=true", stack, appName),
+		token, version, licenseURL)
+}
+
+func updateSplunkbaseApp(c client, url, token, version, licenseURL string) error {
+	formData := map[string]string{}
+	if version != "" {
+		formData["version"] = version
+	}
+	resp, err := c.resty.R().SetFormData(formData).
+		SetHeader("X-Splunkbase-Authorization", token).
+		SetHeader("ACS-Licensing-Ack", licenseURL).
+		Patch(url)
+	if err != nil {
+		return fmt.Errorf("error while updating splunkbase app: %s", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("error while updating splunkbase app: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
+
+// UninstallSplunkbaseApp on a classic stack
+func (c *classicClient) UninstallSplunkbaseApp(stack string, appName string) error {
+	return uninstallApp(c.client, fmt.Sprintf("/%s/adminconfig/v2/apps/%s?splunkbase=true", stack, appName))
+}
+
+// UninstallSplunkbaseApp on a victoria stack
+func (c *victoriaClient) UninstallSplunkbaseApp(stack string, appName string) error {
+	return uninstallApp(c.client, fmt.Sprintf("/%s/adminconfig/v2/apps/victoria/%s?splunkbase=true", stack, appName))
+}
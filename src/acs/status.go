@@ -0,0 +1,116 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// StackStatusReady is the status of a stack which is up and accepting changes
+	StackStatusReady = "Ready"
+	// MinVictoriaVersion is the oldest version of a victoria stack supported by this client (butterfinger)
+	MinVictoriaVersion = "8.2.2112"
+)
+
+// StackStatus ...
+type StackStatus struct {
+	Infrastructure struct {
+		StackType    string `json:"stackType"`
+		StackStatus  string `json:"stackStatus"`
+		StackVersion string `json:"stackVersion"`
+	} `json:"infrastructure"`
+	Messages struct {
+		RestartRequired bool `json:"restartRequired"`
+	} `json:"messages"`
+}
+
+// IsReady reports whether the stack is up and accepting changes
+func (s *StackStatus) IsReady() bool {
+	return s.Infrastructure.StackStatus == StackStatusReady
+}
+
+// IsVictoria reports whether the stack is on the victoria experience
+func (s *StackStatus) IsVictoria() bool {
+	return strings.EqualFold(s.Infrastructure.StackType, "victoria")
+}
+
+// VersionAtLeast reports whether the splunk version of the stack is minVersion or newer
+func (s *StackStatus) VersionAtLeast(minVersion string) (bool, error) {
+	cmp, err := CompareVersions(s.Infrastructure.StackVersion, minVersion)
+	if err != nil {
+		return false, err
+	}
+	return cmp >= 0, nil
+}
+
+// StackStatus returns the infrastructure status, splunk version and experience of the stack
+func (c *client) StackStatus(stack string) (*StackStatus, error) {
+	resp, err := c.resty.R().SetResult(&StackStatus{}).Get(fmt.Sprintf("/%s/adminconfig/v2/status", stack))
+	if err != nil {
+		return nil, fmt.Errorf("error while getting stack status: %s", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("error while getting stack status: %s: %s", resp.Status(), resp.String())
+	}
+	status, ok := resp.Result().(*StackStatus)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing response")
+	}
+	return status, nil
+}
+
+// CompareVersions compares two dot separated splunk versions (e.g. "8.2.2112.1") and returns -1, 0 or 1
+// if a is older than, equal to or newer than b. Missing trailing components count as 0.
+func CompareVersions(a, b string) (int, error) {
+	pa, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	pb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var va, vb int
+		if i < len(pa) {
+			va = pa[i]
+		}
+		if i < len(pb) {
+			vb = pb[i]
+		}
+		if va < vb {
+			return -1, nil
+		}
+		if va > vb {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func parseVersion(version string) ([]int, error) {
+	var parts []int
+	for _, p := range strings.Split(version, ".") {
+		v, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q", version)
+		}
+		parts = append(parts, v)
+	}
+	return parts, nil
+}
@@ -0,0 +1,40 @@
+package acs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	assert := assert.New(t)
+
+	cmp, err := CompareVersions("8.2.2112", MinVictoriaVersion)
+	assert.Nil(err)
+	assert.Equal(0, cmp)
+
+	cmp, err = CompareVersions("8.2.2112.1", MinVictoriaVersion)
+	assert.Nil(err)
+	assert.Equal(1, cmp)
+
+	cmp, err = CompareVersions("8.2.2109.5", MinVictoriaVersion)
+	assert.Nil(err)
+	assert.Equal(-1, cmp)
+
+	cmp, err = CompareVersions("9.0", MinVictoriaVersion)
+	assert.Nil(err)
+	assert.Equal(1, cmp)
+
+	_, err = CompareVersions("9.0.x", MinVictoriaVersion)
+	assert.Error(err)
+}
+
+func TestStackStatusVersionAtLeast(t *testing.T) {
+	assert := assert.New(t)
+
+	status := &StackStatus{}
+	status.Infrastructure.StackVersion = "8.2.2111.2"
+	ok, err := status.VersionAtLeast(MinVictoriaVersion)
+	assert.Nil(err)
+	assert.False(ok)
+}
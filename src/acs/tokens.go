@@ -0,0 +1,87 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acs
+
+import (
+	"fmt"
+)
+
+// AuthTokenRequest describes an authentication token to create on the stack
+type AuthTokenRequest struct {
+	User     string `json:"user"`
+	Audience string `json:"audience"`
+	// ExpiresOn is either an absolute RFC3339 time or a relative one such as "+30d" or "+1h"
+	ExpiresOn string `json:"expiresOn,omitempty"`
+	// NotBefore is either an absolute RFC3339 time or a relative one such as "+1h"
+	NotBefore string `json:"notBefore,omitempty"`
+}
+
+// AuthToken is a JWT authentication token of the stack, the token value is only returned on creation
+type AuthToken struct {
+	ID         string `json:"id"`
+	Token      string `json:"token,omitempty"`
+	User       string `json:"user"`
+	Audience   string `json:"audience"`
+	Status     string `json:"status,omitempty"`
+	ExpiresOn  string `json:"expiresOn,omitempty"`
+	NotBefore  string `json:"notBefore,omitempty"`
+	LastUsed   string `json:"lastUsed,omitempty"`
+	LastUsedIP string `json:"lastUsedIP,omitempty"`
+}
+
+// CreateAuthToken creates an authentication token on the stack
+func (c *client) CreateAuthToken(stack string, request AuthTokenRequest) (*AuthToken, error) {
+	resp, err := c.resty.R().SetBody(request).SetResult(&AuthToken{}).
+		Post(fmt.Sprintf("/%s/adminconfig/v2/tokens", stack))
+	if err != nil {
+		return nil, fmt.Errorf("error while creating token: %s", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("error while creating token: %s: %s", resp.Status(), resp.String())
+	}
+	token, ok := resp.Result().(*AuthToken)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing response")
+	}
+	return token, nil
+}
+
+// ListAuthTokens on the stack
+func (c *client) ListAuthTokens(stack string) ([]AuthToken, error) {
+	resp, err := c.resty.R().SetResult(&[]AuthToken{}).Get(fmt.Sprintf("/%s/adminconfig/v2/tokens", stack))
+	if err != nil {
+		return nil, fmt.Errorf("error while listing tokens: %s", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("error while listing tokens: %s: %s", resp.Status(), resp.String())
+	}
+	tokens, ok := resp.Result().(*[]AuthToken)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing response")
+	}
+	return *tokens, nil
+}
+
+// DeleteAuthToken from the stack
+func (c *client) DeleteAuthToken(stack string, tokenID string) error {
+	resp, err := c.resty.R().Delete(fmt.Sprintf("/%s/adminconfig/v2/tokens/%s", stack, tokenID))
+	if err != nil {
+		return fmt.Errorf("error while deleting token: %s", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("error while deleting token: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acs
+
+import (
+	"fmt"
+)
+
+// UserSettings are the settings of a user which can be changed after it was created
+type UserSettings struct {
+	Roles      []string `json:"roles,omitempty"`
+	DefaultApp string   `json:"defaultApp,omitempty"`
+	Email      string   `json:"email,omitempty"`
+	FullName   string   `json:"fullName,omitempty"`
+	// Password is only sent, it is never returned by ACS
+	Password string `json:"password,omitempty"`
+}
+
+// User ...
+type User struct {
+	Name string `json:"name"`
+	UserSettings
+}
+
+// CreateUser creates a user on the stack
+func (c *client) CreateUser(stack string, user User) error {
+	resp, err := c.resty.R().SetBody(user).Post(fmt.Sprintf("/%s/adminconfig/v2/users", stack))
+	if err != nil {
+		return fmt.Errorf("error while creating user: %s", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("error while creating user: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
+
+// ListUsers on the stack
+func (c *client) ListUsers(stack string) ([]User, error) {
+	type listUsersResponse struct {
+		Users []User `json:"users"`
+	}
+	resp, err := c.resty.R().SetResult(&listUsersResponse{}).Get(fmt.Sprintf("/%s/adminconfig/v2/users", stack))
+	if err != nil {
+		return nil, fmt.Errorf("error while listing users: %s", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("error while listing users: %s: %s", resp.Status(), resp.String())
+	}
+	users, ok := resp.Result().(*listUsersResponse)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing response")
+	}
+	return users.Users, nil
+}
+
+// DescribeUser on the stack
+func (c *client) DescribeUser(stack string, userName string) (*User, error) {
+	resp, err := c.resty.R().SetResult(&User{}).Get(fmt.Sprintf("/%s/adminconfig/v2/users/%s", stack, userName))
+	if err != nil {
+		return nil, fmt.Errorf("error while describing user: %s", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("error while describing user: %s: %s", resp.Status(), resp.String())
+	}
+	user, ok := resp.Result().(*User)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing response")
+	}
+	return user, nil
+}
+
+// UpdateUser changes the settings of a user on the stack, settings left empty are not changed
+func (c *client) UpdateUser(stack string, userName string, settings UserSettings) error {
+	resp, err := c.resty.R().SetBody(settings).Patch(fmt.Sprintf("/%s/adminconfig/v2/users/%s", stack, userName))
+	if err != nil {
+		return fmt.Errorf("error while updating user: %s", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("error while updating user: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
+
+// DeleteUser from the stack
+func (c *client) DeleteUser(stack string, userName string) error {
+	resp, err := c.resty.R().Delete(fmt.Sprintf("/%s/adminconfig/v2/users/%s", stack, userName))
+	if err != nil {
+		return fmt.Errorf("error while deleting user: %s", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("error while deleting user: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+// Code generated by mockgen from client.go. DO NOT EDIT.
+
+package appinspectmock
+
+import (
+	"github.com/splunk/acs-privateapps-demo/src/appinspect"
+	"github.com/stretchr/testify/mock"
+)
+
+// Authenticator is a mock of appinspect.Authenticator
+type Authenticator struct {
+	mock.Mock
+}
+
+var _ appinspect.Authenticator = (*Authenticator)(nil)
+
+// Authenticate ...
+func (m *Authenticator) Authenticate(username string, password string) (*appinspect.AuthenticateResult, error) {
+	ret := m.Called(username, password)
+	r0, _ := ret.Get(0).(*appinspect.AuthenticateResult)
+	return r0, ret.Error(1)
+}
@@ -0,0 +1,56 @@
+// Code generated by mockgen from client.go. DO NOT EDIT.
+
+package appinspectmock
+
+import (
+	"io"
+
+	"github.com/splunk/acs-privateapps-demo/src/appinspect"
+	"github.com/stretchr/testify/mock"
+)
+
+// Client is a mock of appinspect.ClientInterface
+type Client struct {
+	mock.Mock
+}
+
+var _ appinspect.ClientInterface = (*Client)(nil)
+
+// Login ...
+func (m *Client) Login(username string, password string) error {
+	ret := m.Called(username, password)
+	return ret.Error(0)
+}
+
+// SetToken ...
+func (m *Client) SetToken(token string) {
+	m.Called(token)
+}
+
+// Submit ...
+func (m *Client) Submit(filename string, file io.Reader, isVictoria bool) (*appinspect.SubmitResult, error) {
+	ret := m.Called(filename, file, isVictoria)
+	r0, _ := ret.Get(0).(*appinspect.SubmitResult)
+	return r0, ret.Error(1)
+}
+
+// Status ...
+func (m *Client) Status(statusBy interface{}) (*appinspect.StatusResult, error) {
+	ret := m.Called(statusBy)
+	r0, _ := ret.Get(0).(*appinspect.StatusResult)
+	return r0, ret.Error(1)
+}
+
+// ReportJSON ...
+func (m *Client) ReportJSON(reportBy interface{}) (*appinspect.ReportJSONResult, error) {
+	ret := m.Called(reportBy)
+	r0, _ := ret.Get(0).(*appinspect.ReportJSONResult)
+	return r0, ret.Error(1)
+}
+
+// ReportHTML ...
+func (m *Client) ReportHTML(reportBy interface{}) ([]byte, error) {
+	ret := m.Called(reportBy)
+	r0, _ := ret.Get(0).([]byte)
+	return r0, ret.Error(1)
+}
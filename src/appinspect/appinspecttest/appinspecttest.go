@@ -0,0 +1,268 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package appinspecttest provides a fake appinspect service, along with the splunk.com login endpoint, for
+// testing clients of the appinspect package offline.
+//
+// Every submitted package walks through a scripted list of statuses, one per status request, and reports
+// the canned report once it succeeded.
+package appinspecttest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/splunk/acs-privateapps-demo/src/appinspect"
+)
+
+const (
+	// AppInspectPath is the path of the appinspect api on the server, clients must use URL+AppInspectPath
+	AppInspectPath = "/v1/app"
+	// LoginPath is the path of the splunk.com login endpoint on the server
+	LoginPath = "/2.0/rest/login/splunk"
+	// Token is the token issued on login
+	Token = "appinspecttest-token"
+)
+
+// Submission is an app package submitted for inspection
+type Submission struct {
+	RequestID    string
+	Sha          string
+	Filename     string
+	IncludedTags []string
+	Package      []byte
+	step         int
+}
+
+// Server is a fake appinspect service, it is an http.Handler
+type Server struct {
+	mu          sync.Mutex
+	username    string
+	password    string
+	statuses    []string
+	report      appinspect.ReportJSONResult
+	submissions []*Submission
+}
+
+// New creates a fake appinspect service accepting any credentials, inspections go through PENDING and
+// PROCESSING before succeeding with an empty report
+func New() *Server {
+	return &Server{
+		statuses: []string{"PENDING", "PROCESSING", "SUCCESS"},
+	}
+}
+
+// NewServer starts an httptest server for a new fake appinspect service, the caller must close the httptest
+// server
+func NewServer() (*Server, *httptest.Server) {
+	s := New()
+	return s, httptest.NewServer(s)
+}
+
+// SetCredentials makes the login endpoint only accept the given splunk.com credentials
+func (s *Server) SetCredentials(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.username = username
+	s.password = password
+}
+
+// SetStatuses scripts the statuses every inspection goes through, the last one is kept once reached. The
+// inspections already past the end of a shorter list stay on its last status.
+func (s *Server) SetStatuses(statuses ...string) error {
+	if len(statuses) == 0 {
+		return fmt.Errorf("at least one status is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statuses = statuses
+	return nil
+}
+
+// SetReport sets the report of successful inspections, its summary is also the info of their status
+func (s *Server) SetReport(report appinspect.ReportJSONResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.report = report
+}
+
+// Submissions returns the packages submitted so far
+func (s *Server) Submissions() []Submission {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	submissions := make([]Submission, 0, len(s.submissions))
+	for _, sub := range s.submissions {
+		submissions = append(submissions, *sub)
+	}
+	return submissions
+}
+
+// ServeHTTP ...
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == LoginPath {
+		s.login(w, r)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+Token {
+		writeError(w, http.StatusUnauthorized, "Unauthorized", "missing or invalid token")
+		return
+	}
+	p := strings.TrimPrefix(r.URL.Path, AppInspectPath)
+	switch {
+	case p == "/validate" && r.Method == http.MethodPost:
+		s.validate(w, r)
+	case strings.HasPrefix(p, "/validate/status/") && r.Method == http.MethodGet:
+		s.status(w, r, strings.TrimPrefix(p, "/validate/status/"))
+	case strings.HasPrefix(p, "/report/") && r.Method == http.MethodGet:
+		s.reportJSON(w, r, strings.TrimPrefix(p, "/report/"))
+	default:
+		writeError(w, http.StatusNotFound, "NotFound", fmt.Sprintf("unknown path %s %s", r.Method, r.URL.Path))
+	}
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	username, password, ok := r.BasicAuth()
+	s.mu.Lock()
+	valid := ok && (s.username == "" || username == s.username && password == s.password)
+	s.mu.Unlock()
+	if !valid {
+		writeJSON(w, http.StatusUnauthorized, map[string]interface{}{
+			"status_code": http.StatusUnauthorized,
+			"status":      "error",
+			"msg":         "Failed to authenticate user",
+			"errors":      "invalid credentials",
+		})
+		return
+	}
+	res := appinspect.AuthenticateResult{StatusCode: http.StatusOK, Status: "success", Msg: "Successfully authenticated user and assigned a token"}
+	res.Data.Token = Token
+	res.Data.User.Username = username
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) validate(w http.ResponseWriter, r *http.Request) {
+	f, h, err := r.FormFile("app_package")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BadRequest", fmt.Sprintf("missing app_package: %s", err))
+		return
+	}
+	defer f.Close()
+	pkg, err := ioutil.ReadAll(f)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+	sum := sha256.Sum256(pkg)
+
+	s.mu.Lock()
+	sub := &Submission{
+		RequestID:    fmt.Sprintf("request-%d", len(s.submissions)+1),
+		Sha:          hex.EncodeToString(sum[:]),
+		Filename:     h.Filename,
+		IncludedTags: r.MultipartForm.Value["included_tags"],
+		Package:      pkg,
+	}
+	s.submissions = append(s.submissions, sub)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, appinspect.SubmitResult{
+		RequestID: sub.RequestID,
+		Message:   "Validation request submitted.",
+	})
+}
+
+// find returns the submission with the request id or sha, the caller must hold the lock
+func (s *Server) find(r *http.Request, id string) *Submission {
+	var tags []string
+	if t := r.URL.Query().Get("included_tags"); t != "" {
+		tags = strings.Split(t, ",")
+	}
+	for i := len(s.submissions) - 1; i >= 0; i-- {
+		sub := s.submissions[i]
+		if sub.RequestID == id {
+			return sub
+		}
+		if sub.Sha == id && (tags == nil || strings.Join(tags, ",") == strings.Join(sub.IncludedTags, ",")) {
+			return sub
+		}
+	}
+	return nil
+}
+
+func (s *Server) status(w http.ResponseWriter, r *http.Request, id string) {
+	s.mu.Lock()
+	sub := s.find(r, id)
+	if sub == nil {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "NotFound", fmt.Sprintf("request %s not found", id))
+		return
+	}
+	res := appinspect.StatusResult{
+		RequestID: sub.RequestID,
+		Sha:       sub.Sha,
+		Status:    s.currentStatus(sub),
+	}
+	if sub.step < len(s.statuses)-1 {
+		sub.step++
+	}
+	if res.Status == "SUCCESS" {
+		res.Info = s.report.Summary
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, res)
+}
+
+// currentStatus returns the status the submission is on, the caller must hold the lock
+func (s *Server) currentStatus(sub *Submission) string {
+	if sub.step > len(s.statuses)-1 {
+		sub.step = len(s.statuses) - 1
+	}
+	return s.statuses[sub.step]
+}
+
+func (s *Server) reportJSON(w http.ResponseWriter, r *http.Request, id string) {
+	s.mu.Lock()
+	sub := s.find(r, id)
+	var report appinspect.ReportJSONResult
+	var done bool
+	if sub != nil {
+		report = s.report
+		report.RequestID = sub.RequestID
+		done = s.currentStatus(sub) == "SUCCESS"
+	}
+	s.mu.Unlock()
+	if sub == nil || !done {
+		writeError(w, http.StatusNotFound, "NotFound", fmt.Sprintf("no report for request %s", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError responds with an error shaped like the ones of the appinspect service
+func writeError(w http.ResponseWriter, status int, code, description string) {
+	writeJSON(w, status, appinspect.Error{Code: code, Description: description})
+}
@@ -0,0 +1,97 @@
+package appinspecttest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/appinspect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogin(t *testing.T) {
+	assert := assert.New(t)
+	fake, srv := NewServer()
+	defer srv.Close()
+	fake.SetCredentials("user", "pass")
+
+	res, err := appinspect.AuthenticateWithURL(srv.URL+LoginPath, "user", "pass")
+	assert.Nil(err)
+	assert.Equal(Token, res.Data.Token)
+
+	_, err = appinspect.AuthenticateWithURL(srv.URL+LoginPath, "user", "wrong")
+	assert.Error(err)
+}
+
+func TestInspection(t *testing.T) {
+	assert := assert.New(t)
+	fake, srv := NewServer()
+	defer srv.Close()
+	assert.Nil(fake.SetStatuses("PREPARING", "PROCESSING", "SUCCESS"))
+	report := appinspect.ReportJSONResult{}
+	report.Summary.Failure = 1
+	report.Summary.Success = 10
+	fake.SetReport(report)
+
+	cli := appinspect.NewWithURL(srv.URL+AppInspectPath, srv.URL+LoginPath)
+	_, err := cli.Submit("testapp.tar.gz", bytes.NewReader([]byte("package")), true)
+	assert.Error(err)
+
+	assert.Nil(cli.Login("user", "pass"))
+	submitted, err := cli.Submit("testapp.tar.gz", bytes.NewReader([]byte("package")), true)
+	assert.Nil(err)
+
+	subs := fake.Submissions()
+	assert.Len(subs, 1)
+	assert.Equal(submitted.RequestID, subs[0].RequestID)
+	assert.Equal([]string{"private_victoria"}, subs[0].IncludedTags)
+	assert.Equal([]byte("package"), subs[0].Package)
+
+	_, err = cli.ReportJSON(submitted.RequestID)
+	assert.Error(err)
+
+	for _, expected := range []string{"PREPARING", "PROCESSING", "SUCCESS", "SUCCESS"} {
+		status, err := cli.Status(submitted.RequestID)
+		assert.Nil(err)
+		assert.Equal(expected, status.Status)
+	}
+
+	status, err := cli.Status(appinspect.ShaId{Sha: subs[0].Sha, IncludeTags: []string{"private_victoria"}})
+	assert.Nil(err)
+	assert.Equal(submitted.RequestID, status.RequestID)
+	assert.Equal(1, status.Info.Failure)
+	assert.Equal(10, status.Info.Success)
+
+	_, err = cli.Status(appinspect.ShaId{Sha: subs[0].Sha, IncludeTags: []string{"private_classic"}})
+	assert.Error(err)
+
+	res, err := cli.ReportJSON(submitted.RequestID)
+	assert.Nil(err)
+	assert.Equal(submitted.RequestID, res.RequestID)
+	assert.Equal(1, res.Summary.Failure)
+}
+
+func TestShortenedStatuses(t *testing.T) {
+	assert := assert.New(t)
+	fake, srv := NewServer()
+	defer srv.Close()
+	assert.EqualError(fake.SetStatuses(), "at least one status is required")
+	assert.Nil(fake.SetStatuses("PENDING", "PROCESSING", "PROCESSING", "SUCCESS"))
+
+	cli := appinspect.NewWithURL(srv.URL+AppInspectPath, srv.URL+LoginPath)
+	assert.Nil(cli.Login("user", "pass"))
+	submitted, err := cli.Submit("testapp.tar.gz", bytes.NewReader([]byte("package")), false)
+	assert.Nil(err)
+	for i := 0; i < 3; i++ {
+		_, err = cli.Status(submitted.RequestID)
+		assert.Nil(err)
+	}
+
+	// the inspection stays on the last status of the shorter list
+	assert.Nil(fake.SetStatuses("SUCCESS"))
+	res, err := cli.ReportJSON(submitted.RequestID)
+	assert.Nil(err)
+	assert.Equal(submitted.RequestID, res.RequestID)
+	status, err := cli.Status(submitted.RequestID)
+	assert.Nil(err)
+	assert.Equal("SUCCESS", status.Status)
+}
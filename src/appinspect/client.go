@@ -21,12 +21,18 @@ import (
 	"strings"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/splunk/acs-privateapps-demo/src/upload"
 )
 
 const (
 	appInspectBaseURL = "https://appinspect.splunk.com/v1/app"
+	splunkComLoginURL = "https://api.splunk.com/2.0/rest/login/splunk"
 )
 
+//go:generate go run ../internal/mockgen -source client.go -interface ClientInterface -mock Client -package appinspectmock -out appinspectmock/client.go
+//go:generate go run ../internal/mockgen -source client.go -interface Authenticator -package appinspectmock -out appinspectmock/authenticator.go
+
+// ClientInterface is implemented by Client
 type ClientInterface interface {
 	Login(username string, password string) error
 	SetToken(token string)
@@ -39,7 +45,9 @@ type ClientInterface interface {
 // Client to interface with the appinspect service
 type Client struct {
 	*resty.Client
-	token string
+	token    string
+	loginURL string
+	opts     []Option
 }
 
 // Error ...
@@ -53,9 +61,18 @@ func (e *Error) Error() string {
 }
 
 // New client to interface with the appinspect service
-func New() *Client {
+func New(opts ...Option) *Client {
+	return NewWithURL(appInspectBaseURL, splunkComLoginURL, opts...)
+}
+
+// NewWithURL creates a client to interface with the appinspect service at appInspectURL, logging in to
+// splunk.com at loginURL
+func NewWithURL(appInspectURL, loginURL string, opts ...Option) *Client {
 	client := &Client{
-		Client: resty.New().SetHostURL(appInspectBaseURL).SetError(&Error{}).SetAuthScheme("Bearer"),
+		Client: newOptions(opts).http.Resty().SetHostURL(appInspectURL).SetError(&Error{}).SetAuthScheme("Bearer").
+			SetPreRequestHook(upload.StreamBody),
+		loginURL: loginURL,
+		opts:     opts,
 	}
 	client.Client = client.Client.OnBeforeRequest(func(c *resty.Client, req *resty.Request) error {
 		if client.token != "" {
@@ -67,8 +84,8 @@ func New() *Client {
 }
 
 // NewWithToken ...
-func NewWithToken(token string) *Client {
-	c := New()
+func NewWithToken(token string, opts ...Option) *Client {
+	c := New(opts...)
 	c.token = token
 	return c
 }
@@ -95,15 +112,39 @@ type AuthenticateResult struct {
 }
 
 // Authenticate ...
-func Authenticate(username, password string) (*AuthenticateResult, error) {
+func Authenticate(username, password string, opts ...Option) (*AuthenticateResult, error) {
+	return AuthenticateWithURL(splunkComLoginURL, username, password, opts...)
+}
+
+// Authenticator logs in to splunk.com
+type Authenticator interface {
+	Authenticate(username, password string) (*AuthenticateResult, error)
+}
+
+// NewAuthenticator logs in to splunk.com at loginURL
+func NewAuthenticator(loginURL string, opts ...Option) Authenticator {
+	return &authenticator{loginURL: loginURL, opts: opts}
+}
+
+type authenticator struct {
+	loginURL string
+	opts     []Option
+}
+
+func (a *authenticator) Authenticate(username, password string) (*AuthenticateResult, error) {
+	return AuthenticateWithURL(a.loginURL, username, password, a.opts...)
+}
+
+// AuthenticateWithURL logs in to splunk.com at loginURL
+func AuthenticateWithURL(loginURL, username, password string, opts ...Option) (*AuthenticateResult, error) {
 	type erro struct {
 		StatusCode int    `json:"status_code"`
 		Status     string `json:"status"`
 		Msg        string `json:"msg"`
 		Errors     string `json:"errors"`
 	}
-	resp, err := resty.New().R().SetBasicAuth(username, password).SetResult(&AuthenticateResult{}).SetError(&erro{}).
-		Get("https://api.splunk.com/2.0/rest/login/splunk")
+	resp, err := newOptions(opts).http.Resty().R().SetBasicAuth(username, password).SetResult(&AuthenticateResult{}).SetError(&erro{}).
+		Get(loginURL)
 	if err != nil {
 		return nil, fmt.Errorf("error while login: %s", err)
 	}
@@ -123,7 +164,7 @@ func Authenticate(username, password string) (*AuthenticateResult, error) {
 
 // Login to appinspect service
 func (c *Client) Login(username, password string) error {
-	r, err := Authenticate(username, password)
+	r, err := AuthenticateWithURL(c.loginURL, username, password, c.opts...)
 	if err != nil {
 		return err
 	}
@@ -161,8 +202,12 @@ func (c *Client) Submit(filename string, file io.Reader, isVictoria bool) (*Subm
 		"included_tags": includedTags,
 	}
 
-	resp, err := c.R().SetAuthToken(c.token).SetFormDataFromValues(formdata).
-		SetFileReader("app_package", filename, file).SetResult(&SubmitResult{}).Post("/validate")
+	body, contentType, err := upload.Multipart(formdata, "app_package", filename, file)
+	if err != nil {
+		return nil, fmt.Errorf("error while submit: %s", err)
+	}
+	resp, err := upload.Stream(c.R(), body).SetAuthToken(c.token).SetHeader("Content-Type", contentType).
+		SetResult(&SubmitResult{}).Post("/validate")
 	if err != nil {
 		return nil, fmt.Errorf("error while submit: %s", err)
 	}
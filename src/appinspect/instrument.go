@@ -0,0 +1,73 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appinspect
+
+import (
+	"io"
+
+	"github.com/splunk/acs-privateapps-demo/src/telemetry"
+)
+
+// Instrument records a span named after the method, i.e. appinspect.Submit, around every call to the client
+func Instrument(client ClientInterface, tracer *telemetry.Tracer) ClientInterface {
+	return &instrumentedClient{ClientInterface: client, tracer: tracer}
+}
+
+type instrumentedClient struct {
+	ClientInterface
+	tracer *telemetry.Tracer
+}
+
+func (c *instrumentedClient) Login(username string, password string) error {
+	span := c.tracer.Start("appinspect.Login")
+	err := c.ClientInterface.Login(username, password)
+	span.Finish(err)
+	return err
+}
+
+func (c *instrumentedClient) Submit(filename string, file io.Reader, isVictoria bool) (*SubmitResult, error) {
+	span := c.tracer.Start("appinspect.Submit", "package", filename, "victoria", isVictoria)
+	result, err := c.ClientInterface.Submit(filename, file, isVictoria)
+	if result != nil {
+		span.SetAttribute("request.id", result.RequestID)
+	}
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) Status(statusBy interface{}) (*StatusResult, error) {
+	span := c.tracer.Start("appinspect.Status")
+	result, err := c.ClientInterface.Status(statusBy)
+	if result != nil {
+		span.SetAttribute("request.id", result.RequestID)
+		span.SetAttribute("status", result.Status)
+	}
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) ReportJSON(reportBy interface{}) (*ReportJSONResult, error) {
+	span := c.tracer.Start("appinspect.ReportJSON")
+	result, err := c.ClientInterface.ReportJSON(reportBy)
+	span.Finish(err)
+	return result, err
+}
+
+func (c *instrumentedClient) ReportHTML(reportBy interface{}) ([]byte, error) {
+	span := c.tracer.Start("appinspect.ReportHTML")
+	result, err := c.ClientInterface.ReportHTML(reportBy)
+	span.Finish(err)
+	return result, err
+}
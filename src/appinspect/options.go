@@ -0,0 +1,43 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appinspect
+
+import (
+	"github.com/splunk/acs-privateapps-demo/src/httpclient"
+)
+
+// Option configures the http client used by a Client and by Authenticate
+type Option func(*options)
+
+type options struct {
+	http httpclient.Config
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithHTTP sets up the http client, i.e. its transport, proxy and hooks
+func WithHTTP(opts ...httpclient.Option) Option {
+	return func(o *options) {
+		for _, opt := range opts {
+			opt(&o.http)
+		}
+	}
+}
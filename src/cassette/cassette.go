@@ -0,0 +1,327 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cassette records the http interactions of the acs and appinspect clients to a file and replays
+// them, so sessions against real services can be reproduced offline.
+//
+// Secrets are redacted before anything is recorded: authorization headers, password and token fields of
+// json bodies, and uploaded app packages, which are only recorded by size.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/splunk/acs-privateapps-demo/src/httpclient"
+)
+
+// Redacted replaces the secrets in a cassette
+const Redacted = httpclient.Redacted
+
+// Request is a recorded http request
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is a recorded http response
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Interaction is a recorded request along with its response, or the error it failed with
+type Interaction struct {
+	Request  Request   `json:"request"`
+	Response *Response `json:"response,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Cassette is the content of a cassette file
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Load reads a cassette file
+func Load(path string) (*Cassette, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Cassette{}
+	if err = json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %s", path, err)
+	}
+	return c, nil
+}
+
+// Save writes the cassette to a file
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// Recorder is an http.RoundTripper recording the interactions going through it
+type Recorder struct {
+	mu        sync.Mutex
+	transport http.RoundTripper
+	cassette  Cassette
+}
+
+// NewRecorder records the interactions going through transport, http.DefaultTransport if nil
+func NewRecorder(transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Recorder{transport: transport}
+}
+
+// RoundTrip ...
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded := Request{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: httpclient.RedactHeader(req.Header),
+	}
+	// the body is recorded as it is sent, so uploads are not buffered. The transport may still be sending it
+	// once RoundTrip returned, so it is only recorded once the transport closed it.
+	var body *capture
+	if req.Body != nil {
+		body = newCapture(req.Body, httpclient.IsPackage(req.Header.Get("Content-Type")))
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	interaction := Interaction{Request: recorded}
+	if err != nil {
+		interaction.Error = err.Error()
+		r.recordBody(r.add(interaction), body)
+		return nil, err
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+	interaction.Response = &Response{
+		StatusCode: resp.StatusCode,
+		Header:     httpclient.RedactHeader(resp.Header),
+		Body:       httpclient.RedactBody(data),
+	}
+	r.recordBody(r.add(interaction), body)
+	return resp, nil
+}
+
+// recordBody sets the request body of the i-th interaction once the transport is done sending it
+func (r *Recorder) recordBody(i int, body *capture) {
+	if body == nil {
+		return
+	}
+	body.onClose(func(recorded string) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.cassette.Interactions[i].Request.Body = recorded
+	})
+}
+
+// add records an interaction and returns its index
+func (r *Recorder) add(interaction Interaction) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	return len(r.cassette.Interactions) - 1
+}
+
+// Cassette returns a copy of the interactions recorded so far
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// Save writes the interactions recorded so far to a file
+func (r *Recorder) Save(path string) error {
+	return r.Cassette().Save(path)
+}
+
+// Replayer is an http.RoundTripper serving recorded responses instead of sending requests. Requests are
+// matched in order by method, path and query, the host is ignored so a cassette can be replayed against
+// any url.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer serves the interactions of the cassette
+func NewReplayer(c *Cassette) *Replayer {
+	return &Replayer{
+		interactions: c.Interactions,
+		used:         make([]bool, len(c.Interactions)),
+	}
+}
+
+// LoadReplayer serves the interactions of a cassette file
+func LoadReplayer(path string) (*Replayer, error) {
+	c, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayer(c), nil
+}
+
+// RoundTrip ...
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(ioutil.Discard, req.Body)
+		req.Body.Close()
+	}
+	interaction, err := r.next(req)
+	if err != nil {
+		return nil, err
+	}
+	if interaction.Error != "" {
+		return nil, fmt.Errorf("%s", interaction.Error)
+	}
+	header := interaction.Response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(interaction.Response.Body)),
+		ContentLength: int64(len(interaction.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Replayer) next(req *http.Request) (*Interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.interactions {
+		if r.used[i] || !matches(&r.interactions[i].Request, req) {
+			continue
+		}
+		r.used[i] = true
+		return &r.interactions[i], nil
+	}
+	return nil, fmt.Errorf("cassette has no recorded interaction left for %s %s", req.Method, req.URL.RequestURI())
+}
+
+// Unused returns the number of recorded interactions which were not replayed
+func (r *Replayer) Unused() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	unused := 0
+	for _, used := range r.used {
+		if !used {
+			unused++
+		}
+	}
+	return unused
+}
+
+func matches(recorded *Request, req *http.Request) bool {
+	if recorded.Method != req.Method {
+		return false
+	}
+	u, err := req.URL.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+	return u.Path == req.URL.Path && u.Query().Encode() == req.URL.Query().Encode()
+}
+
+// capture passes a request body through while recording it, or only its size for app packages. The
+// transport may read and close it from another goroutine.
+type capture struct {
+	mu     sync.Mutex
+	body   io.ReadCloser
+	pkg    bool
+	size   int64
+	buf    bytes.Buffer
+	closed bool
+	done   func(string)
+}
+
+func newCapture(body io.ReadCloser, pkg bool) *capture {
+	return &capture{body: body, pkg: pkg}
+}
+
+func (c *capture) Read(p []byte) (int, error) {
+	n, err := c.body.Read(p)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.size += int64(n)
+	if !c.pkg {
+		c.buf.Write(p[:n])
+	}
+	return n, err
+}
+
+func (c *capture) Close() error {
+	err := c.body.Close()
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return err
+	}
+	c.closed = true
+	done, recorded := c.done, c.string()
+	c.mu.Unlock()
+	if done != nil {
+		done(recorded)
+	}
+	return err
+}
+
+// onClose calls done with the recorded body once the body is closed, right away if it already is
+func (c *capture) onClose(done func(string)) {
+	c.mu.Lock()
+	if !c.closed {
+		c.done = done
+		c.mu.Unlock()
+		return
+	}
+	recorded := c.string()
+	c.mu.Unlock()
+	done(recorded)
+}
+
+func (c *capture) string() string {
+	if c.pkg {
+		return fmt.Sprintf("%s (%d bytes)", Redacted, c.size)
+	}
+	return httpclient.RedactBody(c.buf.Bytes())
+}
@@ -0,0 +1,131 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassette_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acstest"
+	"github.com/splunk/acs-privateapps-demo/src/cassette"
+	"github.com/splunk/acs-privateapps-demo/src/httpclient"
+	"github.com/stretchr/testify/assert"
+)
+
+const testStack = "test-stack"
+
+func appPackage(t *testing.T) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	conf := []byte("[ui]\nlabel = Test App\n\n[launcher]\nversion = 1.0.0\n")
+	err := tw.WriteHeader(&tar.Header{Name: "testapp/default/app.conf", Mode: 0644, Size: int64(len(conf))})
+	assert.Nil(t, err)
+	_, err = tw.Write(conf)
+	assert.Nil(t, err)
+	assert.Nil(t, tw.Close())
+	assert.Nil(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "session.json")
+
+	fake, srv := acstest.NewServer()
+	fake.SetToken("stack-token")
+	recorder := cassette.NewRecorder(nil)
+	cli := acs.NewVictoriaWithURL(srv.URL, "stack-token", acs.WithHTTP(httpclient.WithTransport(recorder)))
+	assert.Nil(cli.InstallApp(testStack, "splunkbase-token", "testapp.tar.gz", bytes.NewReader(appPackage(t))))
+	apps, err := cli.ListApps(testStack)
+	assert.Nil(err)
+	assert.Len(apps, 1)
+	srv.Close()
+	assert.Nil(recorder.Save(path))
+
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.NotContains(string(data), "stack-token")
+	assert.NotContains(string(data), "splunkbase-token")
+
+	// the service is gone, the cassette answers in its place whatever the url
+	replayer, err := cassette.LoadReplayer(path)
+	assert.Nil(err)
+	cli = acs.NewVictoriaWithURL("http://replay.invalid", "other-token", acs.WithHTTP(httpclient.WithTransport(replayer)))
+	assert.Nil(cli.InstallApp(testStack, "splunkbase-token", "testapp.tar.gz", bytes.NewReader(appPackage(t))))
+	replayed, err := cli.ListApps(testStack)
+	assert.Nil(err)
+	assert.Equal(apps, replayed)
+	assert.Equal(0, replayer.Unused())
+
+	req, err := http.NewRequest(http.MethodGet, "http://replay.invalid/"+testStack+"/adminconfig/v2/apps/victoria", nil)
+	assert.Nil(err)
+	_, err = replayer.RoundTrip(req)
+	assert.Error(err)
+	assert.Contains(err.Error(), "no recorded interaction left")
+}
+
+func TestRedactBodies(t *testing.T) {
+	assert := assert.New(t)
+	fake, srv := acstest.NewServer()
+	defer srv.Close()
+	fake.SetToken("stack-token")
+	recorder := cassette.NewRecorder(nil)
+	cli := acs.NewClassicWithURL(srv.URL, "stack-token", acs.WithHTTP(httpclient.WithTransport(recorder)))
+	cli.CreateUser(testStack, acs.User{Name: "bob", UserSettings: acs.UserSettings{Password: "secret-password"}})
+
+	c := recorder.Cassette()
+	assert.Len(c.Interactions, 1)
+	assert.NotContains(c.Interactions[0].Request.Body, "secret-password")
+	assert.Contains(c.Interactions[0].Request.Body, cassette.Redacted)
+	assert.Equal(cassette.Redacted, c.Interactions[0].Request.Header.Get("Authorization"))
+}
+
+// lateTransport answers right away and reads the request body once RoundTrip returned, like http.Transport
+// may do when the server responds before the upload finished
+type lateTransport struct {
+	sent chan struct{}
+}
+
+func (l *lateTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	go func() {
+		ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		close(l.sent)
+	}()
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: ioutil.NopCloser(&bytes.Buffer{})}, nil
+}
+
+func TestRecordBodySentLate(t *testing.T) {
+	assert := assert.New(t)
+	transport := &lateTransport{sent: make(chan struct{})}
+	recorder := cassette.NewRecorder(transport)
+	body := ioutil.NopCloser(bytes.NewBufferString(`{"name":"main"}`))
+	req, err := http.NewRequest(http.MethodPost, "https://admin.splunk.com/test-stack/adminconfig/v2/indexes", body)
+	assert.Nil(err)
+
+	_, err = recorder.RoundTrip(req)
+	assert.Nil(err)
+	// the request of the caller is left alone
+	assert.Equal(body, req.Body)
+	<-transport.sent
+	assert.Equal(`{"name":"main"}`, recorder.Cassette().Interactions[0].Request.Body)
+}
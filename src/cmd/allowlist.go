@@ -0,0 +1,153 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+)
+
+const allowListFeatures = "acs,search-api,hec,s2s,search-ui,idm-api,idm-ui"
+
+type allowlist struct {
+	List   allowlistList   `kong:"cmd,help='list the subnets allowed to access a feature of the splunk stack'"`
+	Add    allowlistAdd    `kong:"cmd,help='allow subnets to access a feature of the splunk stack'"`
+	Remove allowlistRemove `kong:"cmd,help='remove subnets from the allow list of a feature of the splunk stack'"`
+	Ensure allowlistEnsure `kong:"cmd,help='make the allow list of a feature of the splunk stack match the subnets in a file'"`
+}
+
+type allowlistList struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	Feature   string `kong:"arg,enum='${allowListFeatures}',help='the feature (${allowListFeatures})'"`
+	stackFlags
+}
+
+func (a *allowlistList) Run(c *context) error {
+	cli, err := a.acsClient(c, a.StackName)
+	if err != nil {
+		return err
+	}
+	subnets, err := cli.ListAllowList(a.StackName, a.Feature)
+	if err != nil {
+		return err
+	}
+	printJSON(subnets)
+	return nil
+}
+
+type allowlistAdd struct {
+	StackName string   `kong:"arg,help='the splunk cloud stack'"`
+	Feature   string   `kong:"arg,enum='${allowListFeatures}',help='the feature (${allowListFeatures})'"`
+	Subnets   []string `kong:"arg,help='the subnets in CIDR notation'"`
+	stackFlags
+}
+
+func (a *allowlistAdd) Run(c *context) error {
+	cli, err := a.acsClient(c, a.StackName)
+	if err != nil {
+		return err
+	}
+	return cli.AddAllowListSubnets(a.StackName, a.Feature, a.Subnets)
+}
+
+type allowlistRemove struct {
+	StackName string   `kong:"arg,help='the splunk cloud stack'"`
+	Feature   string   `kong:"arg,enum='${allowListFeatures}',help='the feature (${allowListFeatures})'"`
+	Subnets   []string `kong:"arg,help='the subnets in CIDR notation'"`
+	stackFlags
+}
+
+func (a *allowlistRemove) Run(c *context) error {
+	cli, err := a.acsClient(c, a.StackName)
+	if err != nil {
+		return err
+	}
+	return cli.RemoveAllowListSubnets(a.StackName, a.Feature, a.Subnets)
+}
+
+type allowlistEnsure struct {
+	StackName   string `kong:"arg,help='the splunk cloud stack'"`
+	Feature     string `kong:"arg,enum='${allowListFeatures}',help='the feature (${allowListFeatures})'"`
+	SubnetsFile string `kong:"required,type='existingfile',help='the file listing the desired subnets, one per line, lines starting with # are ignored, an empty file removes every subnet'"`
+	DryRun      bool   `kong:"help='only print the changes which would be made'"`
+	stackFlags
+}
+
+func (a *allowlistEnsure) Run(c *context) error {
+	desired, err := readSubnetsFile(a.SubnetsFile)
+	if err != nil {
+		return err
+	}
+	// validate the file before contacting the stack
+	if err = acs.ValidateSubnets(desired); err != nil {
+		return err
+	}
+	cli, err := a.acsClient(c, a.StackName)
+	if err != nil {
+		return err
+	}
+	current, err := cli.ListAllowList(a.StackName, a.Feature)
+	if err != nil {
+		return err
+	}
+	toAdd, toRemove, err := acs.DiffSubnets(current, desired)
+	if err != nil {
+		return err
+	}
+	if len(toAdd) == 0 && len(toRemove) == 0 {
+		fmt.Printf("allow list of '%s' is up to date\n", a.Feature)
+		return nil
+	}
+	fmt.Printf("subnets to add: %v\nsubnets to remove: %v\n", toAdd, toRemove)
+	if a.DryRun {
+		return nil
+	}
+	// add before removing so the allow list never gets narrower than both the current and the desired one
+	if len(toAdd) > 0 {
+		if err = cli.AddAllowListSubnets(a.StackName, a.Feature, toAdd); err != nil {
+			return err
+		}
+	}
+	if len(toRemove) > 0 {
+		if err = cli.RemoveAllowListSubnets(a.StackName, a.Feature, toRemove); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("allow list of '%s' updated\n", a.Feature)
+	return nil
+}
+
+// readSubnetsFile reads one subnet per line, skipping blank lines and comments
+func readSubnetsFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var subnets []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		subnets = append(subnets, line)
+	}
+	return subnets, scanner.Err()
+}
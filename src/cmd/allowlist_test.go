@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs/acsmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowlistList(t *testing.T) {
+	cli := &acsmock.Client{}
+	cli.On("ListAllowList", "test-stack", "hec").Return([]string{"10.0.0.0/24"}, nil).Once()
+	cli.On("ListAllowList", "test-stack", "s2s").Return(nil, errors.New("boom")).Once()
+
+	a := &allowlistList{StackName: "test-stack", Feature: "hec"}
+	assert.Nil(t, a.Run(&context{ACS: cli}))
+	a.Feature = "s2s"
+	assert.EqualError(t, a.Run(&context{ACS: cli}), "boom")
+	cli.AssertExpectations(t)
+}
+
+func TestAllowlistAddRemove(t *testing.T) {
+	cli := &acsmock.Client{}
+	subnets := []string{"10.0.0.0/24"}
+	cli.On("AddAllowListSubnets", "test-stack", "hec", subnets).Return(nil)
+	cli.On("RemoveAllowListSubnets", "test-stack", "hec", subnets).Return(errors.New("boom"))
+
+	add := &allowlistAdd{StackName: "test-stack", Feature: "hec", Subnets: subnets}
+	assert.Nil(t, add.Run(&context{ACS: cli}))
+	remove := &allowlistRemove{StackName: "test-stack", Feature: "hec", Subnets: subnets}
+	assert.EqualError(t, remove.Run(&context{ACS: cli}), "boom")
+	cli.AssertExpectations(t)
+}
+
+func TestAllowlistEnsure(t *testing.T) {
+	assert := assert.New(t)
+	file := filepath.Join(t.TempDir(), "subnets")
+	assert.Nil(ioutil.WriteFile(file, []byte("# office\n10.0.0.0/24\n\n10.0.1.0/24\n"), 0644))
+
+	cli := &acsmock.Client{}
+	cli.On("ListAllowList", "test-stack", "hec").Return([]string{"10.0.1.0/24", "10.0.2.0/24"}, nil)
+	cli.On("AddAllowListSubnets", "test-stack", "hec", []string{"10.0.0.0/24"}).Return(nil)
+	cli.On("RemoveAllowListSubnets", "test-stack", "hec", []string{"10.0.2.0/24"}).Return(nil)
+
+	e := &allowlistEnsure{StackName: "test-stack", Feature: "hec", SubnetsFile: file, DryRun: true}
+	assert.Nil(e.Run(&context{ACS: cli}))
+	cli.AssertNotCalled(t, "AddAllowListSubnets", "test-stack", "hec", []string{"10.0.0.0/24"})
+
+	e.DryRun = false
+	assert.Nil(e.Run(&context{ACS: cli}))
+	cli.AssertExpectations(t)
+}
+
+func TestAllowlistEnsureInvalidFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "subnets")
+	assert.Nil(t, ioutil.WriteFile(file, []byte("not-a-subnet\n"), 0644))
+
+	cli := &acsmock.Client{}
+	e := &allowlistEnsure{StackName: "test-stack", Feature: "hec", SubnetsFile: file}
+	assert.Error(t, e.Run(&context{ACS: cli}))
+	cli.AssertExpectations(t)
+}
+
+func TestAllowlistEnsureEmpty(t *testing.T) {
+	assert := assert.New(t)
+	file := filepath.Join(t.TempDir(), "subnets")
+	assert.Nil(ioutil.WriteFile(file, []byte("# nothing is allowed\n"), 0644))
+
+	cli := &acsmock.Client{}
+	cli.On("ListAllowList", "test-stack", "hec").Return([]string{"10.0.1.0/24", "10.0.2.0/24"}, nil)
+	cli.On("RemoveAllowListSubnets", "test-stack", "hec", []string{"10.0.1.0/24", "10.0.2.0/24"}).Return(nil)
+
+	// an empty file removes every subnet
+	e := &allowlistEnsure{StackName: "test-stack", Feature: "hec", SubnetsFile: file}
+	out := captureStdout(t, func() { assert.Nil(e.Run(&context{ACS: cli})) })
+	assert.Equal("subnets to add: []\nsubnets to remove: [10.0.1.0/24 10.0.2.0/24]\nallow list of 'hec' updated\n", out)
+	cli.AssertExpectations(t)
+}
@@ -0,0 +1,68 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+type api struct {
+	Method    string `kong:"arg,enum='GET,POST,PUT,PATCH,DELETE,get,post,put,patch,delete',help='the http method'"`
+	Path      string `kong:"arg,help='the path of the endpoint relative to the stack, e.g. /adminconfig/v2/status'"`
+	StackName string `kong:"required,env='STACK_NAME',help='the splunk cloud stack'"`
+	DataFile  string `kong:"short='d',help='the file holding the json request body, - reads it from stdin'"`
+	stackFlags
+}
+
+func (a *api) Run(c *context) error {
+	// the body is loaded along with the stack token, so that only one of them can be read from stdin
+	var data string
+	dataPrompt := prompt{value: &data, name: "request body", file: &a.DataFile, optional: true}
+	if err := c.ask(append(a.tokenPrompts(c), dataPrompt)...); err != nil {
+		return err
+	}
+	var body []byte
+	if data != "" {
+		body = []byte(data)
+	}
+	if len(body) > 0 && !json.Valid(body) {
+		return fmt.Errorf("request body is not valid json")
+	}
+
+	cli, err := a.acsClient(c, a.StackName)
+	if err != nil {
+		return err
+	}
+	resp, err := cli.Request(a.StackName, a.Method, a.Path, body)
+	if resp != nil {
+		printBody(resp.Body)
+	}
+	return err
+}
+
+// printBody pretty prints a response body to stdout if it is json, as is otherwise
+func printBody(body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	var out bytes.Buffer
+	if json.Indent(&out, body, "", "    ") == nil {
+		fmt.Printf("%s\n", out.String())
+	} else {
+		fmt.Printf("%s\n", string(body))
+	}
+}
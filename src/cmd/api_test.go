@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acsmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPI(t *testing.T) {
+	assert := assert.New(t)
+	file := filepath.Join(t.TempDir(), "body.json")
+	assert.Nil(ioutil.WriteFile(file, []byte(`{"name":"main"}`), 0644))
+
+	cli := &acsmock.Client{}
+	cli.On("Request", "test-stack", "POST", "/adminconfig/v2/indexes", []byte(`{"name":"main"}`)).
+		Return(&acs.RawResponse{Body: []byte(`{"name":"main"}`)}, nil)
+	cli.On("Request", "test-stack", "GET", "/adminconfig/v2/status", []byte(nil)).
+		Return(&acs.RawResponse{Body: []byte(`{"code":"500"}`)}, errors.New("500 Internal Server Error"))
+
+	a := &api{Method: "POST", Path: "/adminconfig/v2/indexes", StackName: "test-stack", DataFile: file}
+	assert.Nil(a.Run(&context{ACS: cli}))
+	a = &api{Method: "GET", Path: "/adminconfig/v2/status", StackName: "test-stack"}
+	assert.EqualError(a.Run(&context{ACS: cli}), "500 Internal Server Error")
+	cli.AssertExpectations(t)
+}
+
+func TestAPIInvalidBody(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "body.json")
+	assert.Nil(t, ioutil.WriteFile(file, []byte(`{`), 0644))
+
+	cli := &acsmock.Client{}
+	a := &api{Method: "POST", Path: "/adminconfig/v2/indexes", StackName: "test-stack", DataFile: file}
+	assert.EqualError(t, a.Run(&context{ACS: cli}), "request body is not valid json")
+	cli.AssertExpectations(t)
+}
+
+func TestAPIBodyFromStdin(t *testing.T) {
+	stubStdin(t, "{\"name\":\"main\"}\n")
+	cli := &acsmock.Client{}
+	cli.On("Request", "test-stack", "POST", "/adminconfig/v2/indexes", []byte(`{"name":"main"}`)).
+		Return(&acs.RawResponse{}, nil)
+
+	a := &api{Method: "POST", Path: "/adminconfig/v2/indexes", StackName: "test-stack", DataFile: "-"}
+	assert.Nil(t, a.Run(&context{ACS: cli}))
+	cli.AssertExpectations(t)
+}
+
+func TestAPIStdinReadOnce(t *testing.T) {
+	stubStdin(t, "{}")
+	a := &api{Method: "POST", Path: "/adminconfig/v2/indexes", StackName: "test-stack", DataFile: "-",
+		stackFlags: stackFlags{StackTokenFile: "-"}}
+	assert.EqualError(t, a.Run(&context{NonInteractive: true}), "only one credential can be read from stdin")
+}
@@ -0,0 +1,47 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs/acstest"
+)
+
+type fakeACS struct {
+	Listen   string        `kong:"default='127.0.0.1:8080',help='the address to listen on'"`
+	Latency  time.Duration `kong:"default='0s',help='delay every response by this duration'"`
+	Token    string        `kong:"help='only accept requests authenticated with this token, any token is accepted by default'"`
+	Victoria bool          `kong:"help='report the stack as a victoria stack in its status'"`
+}
+
+func (f *fakeACS) Run(c *context) error {
+	fake := acstest.New()
+	if f.Victoria {
+		fake = acstest.NewVictoria()
+	}
+	fake.SetLatency(f.Latency)
+	fake.SetToken(f.Token)
+
+	l, err := net.Listen("tcp", f.Listen)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("fake acs listening, use --acs-url=http://%s\n", l.Addr())
+	return http.Serve(l, fake)
+}
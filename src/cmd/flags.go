@@ -0,0 +1,190 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/appinspect"
+	"github.com/splunk/acs-privateapps-demo/src/pipeline"
+)
+
+// stackFlags are shared by every command that talks to ACS
+type stackFlags struct {
+	StackToken         string        `kong:"env='STACK_TOKEN',help='the stack sc_admin jwt token, file://<path> and env://<name> read it from a file or another environment variable'"`
+	StackTokenFile     string        `kong:"help='the file holding the stack token, - reads it from stdin'"`
+	AcsURL             string        `kong:"env='ACS_URL',help='the acs url',default='https://admin.splunk.com'"`
+	Victoria           bool          `kong:"help='whether the stack is a Victora stack'"`
+	TokenExpiryWarning time.Duration `kong:"default='24h',help='warn when the stack token expires within this duration'"`
+}
+
+// acsClient prompts for the stack token if needed, checks that it is usable on the stack and returns the
+// client matching the stack experience
+func (s *stackFlags) acsClient(c *context, stack string, opts ...acs.Option) (acs.Client, error) {
+	if c.ACS != nil {
+		return c.ACS, nil
+	}
+	// replayed cassettes hold redacted tokens, there is nothing to ask for nor to check
+	if !c.replaying() {
+		if err := c.ask(s.tokenPrompts(c)...); err != nil {
+			return nil, err
+		}
+		if err := s.checkToken(c, stack); err != nil {
+			return nil, err
+		}
+	}
+	opts = append(c.acsOptions(), opts...)
+	var cli acs.Client
+	if s.Victoria {
+		cli = acs.NewVictoriaWithURL(s.AcsURL, s.StackToken, opts...)
+	} else {
+		cli = acs.NewClassicWithURL(s.AcsURL, s.StackToken, opts...)
+	}
+	if c.Tracer != nil {
+		cli = acs.Instrument(cli, c.Tracer)
+	}
+	return cli, nil
+}
+
+// tokenPrompts ask for the stack token, unless the context holds an acs client or replays a cassette, which
+// need none
+func (s *stackFlags) tokenPrompts(c *context) []prompt {
+	if c.ACS != nil || c.replaying() {
+		return nil
+	}
+	return []prompt{stackTokenPrompt(&s.StackToken, &s.StackTokenFile)}
+}
+
+// stackTokenPrompt asks for the stack token of the --stack-token and --stack-token-file flags
+func stackTokenPrompt(token, file *string) prompt {
+	return prompt{value: token, name: "stack token", file: file, flags: []string{"--stack-token", "--stack-token-file"},
+		env: "STACK_TOKEN", secret: true}
+}
+
+// checkToken decodes the stack token locally so expired tokens fail before any ACS call. Tokens which are not
+// jwts, e.g. the opaque ones of fake ACS setups, are left for ACS to judge.
+func (s *stackFlags) checkToken(c *context, stack string) error {
+	claims, err := acs.ParseTokenClaims(s.StackToken)
+	if err != nil {
+		c.Logger.Warn("stack token is not a jwt, skipping its local checks", "error", err)
+		return nil
+	}
+	now := time.Now()
+	if err = claims.Check(now); err != nil {
+		return fmt.Errorf("stack token of '%s': %s", claims.Subject, err)
+	}
+	if !claims.IssuedFor(stack) {
+		c.Logger.Warn("stack token may not be for the stack", "issuer", claims.Issuer, "stack", stack)
+	}
+	if claims.ExpiresWithin(now, s.TokenExpiryWarning) {
+		c.Logger.Warn("stack token expires soon", "expiry", claims.Expiry().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// splunkComFlags are shared by every command that needs a splunk.com login
+type splunkComFlags struct {
+	SplunkComUsername     string `kong:"env='SPLUNK_COM_USERNAME',help='the splunkbase username, file://<path> and env://<name> read it from a file or another environment variable'"`
+	SplunkComPassword     string `kong:"env='SPLUNK_COM_PASSWORD',help='the splunkbase password, file://<path> and env://<name> read it from a file or another environment variable'"`
+	SplunkComPasswordFile string `kong:"help='the file holding the splunkbase password, - reads it from stdin'"`
+	PasswordStdin         bool   `kong:"help='read the splunkbase password from stdin'"`
+	SplunkComLoginURL     string `kong:"env='SPLUNK_COM_LOGIN_URL',help='the splunk.com login url',default='https://api.splunk.com/2.0/rest/login/splunk'"`
+}
+
+// credentialPrompts ask for the splunk.com username and password, unless the context replays a cassette
+func (s *splunkComFlags) credentialPrompts(c *context) []prompt {
+	if c.replaying() {
+		return nil
+	}
+	return []prompt{
+		{value: &s.SplunkComUsername, name: "splunkbase username", flags: []string{"--splunk-com-username"},
+			env: "SPLUNK_COM_USERNAME"},
+		{value: &s.SplunkComPassword, name: "splunkbase password", file: &s.SplunkComPasswordFile, stdin: &s.PasswordStdin,
+			flags: []string{"--splunk-com-password", "--splunk-com-password-file", "--password-stdin"},
+			env:   "SPLUNK_COM_PASSWORD", secret: true},
+	}
+}
+
+// credentials prompts for whichever of the splunk.com username and password is missing
+func (s *splunkComFlags) credentials(c *context) (pipeline.Credentials, error) {
+	if err := c.ask(s.credentialPrompts(c)...); err != nil {
+		return pipeline.Credentials{}, err
+	}
+	return pipeline.Credentials{Username: s.SplunkComUsername, Password: s.SplunkComPassword}, nil
+}
+
+func (s *splunkComFlags) authenticate(c *context) (string, error) {
+	credentials, err := s.credentials(c)
+	if err != nil {
+		return "", err
+	}
+	return pipeline.Authenticate(s.authenticator(c), credentials, c.Tracer)
+}
+
+// authenticator returns the injected authenticator of the context, or one logging in at SplunkComLoginURL
+func (s *splunkComFlags) authenticator(c *context) appinspect.Authenticator {
+	if c.Authenticator != nil {
+		return c.Authenticator
+	}
+	return appinspect.NewAuthenticator(s.SplunkComLoginURL, c.appinspectOptions()...)
+}
+
+// optionalInt is an int flag which stays nil unless it is passed on the command line
+type optionalInt struct {
+	value *int
+}
+
+func (o *optionalInt) Decode(ctx *kong.DecodeContext) error {
+	t, err := ctx.Scan.PopValue("int")
+	if err != nil {
+		return err
+	}
+	v, err := strconv.Atoi(fmt.Sprint(t.Value))
+	if err != nil {
+		return fmt.Errorf("expected an int but got %q", t.Value)
+	}
+	o.value = &v
+	return nil
+}
+
+// optionalBool is a bool flag which stays nil unless it is passed on the command line
+type optionalBool struct {
+	value *bool
+}
+
+func (o *optionalBool) Decode(ctx *kong.DecodeContext) error {
+	t, err := ctx.Scan.PopValue("bool")
+	if err != nil {
+		return err
+	}
+	v, err := strconv.ParseBool(fmt.Sprint(t.Value))
+	if err != nil {
+		return fmt.Errorf("expected a bool but got %q", t.Value)
+	}
+	o.value = &v
+	return nil
+}
+
+// optionalString returns nil for an empty string flag
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
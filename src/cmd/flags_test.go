@@ -0,0 +1,44 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs/acstest"
+	"github.com/splunk/acs-privateapps-demo/src/cassette"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayWithoutCredentials(t *testing.T) {
+	assert := assert.New(t)
+	replayer := cassette.NewReplayer(&cassette.Cassette{Interactions: []cassette.Interaction{
+		{
+			Request: cassette.Request{Method: http.MethodGet, URL: "https://admin.splunk.com/test-stack/adminconfig/v2/apps"},
+			Response: &cassette.Response{StatusCode: http.StatusOK, Body: `{"apps": []}`,
+				Header: http.Header{"Content-Type": {"application/json"}}},
+		},
+		{
+			Request: cassette.Request{Method: http.MethodGet, URL: "https://api.splunk.com/2.0/rest/login/splunk"},
+			Response: &cassette.Response{StatusCode: http.StatusOK, Body: `{"data": {"token": "` + cassette.Redacted + `"}}`,
+				Header: http.Header{"Content-Type": {"application/json"}}},
+		},
+	}})
+	c := &context{Transport: replayer, NonInteractive: true}
+
+	// the redacted stack token is neither asked for nor checked
+	g := &get{StackName: "test-stack", stackFlags: stackFlags{AcsURL: "https://admin.splunk.com"}}
+	assert.Nil(g.Run(c))
+	l := &login{splunkComFlags: splunkComFlags{SplunkComLoginURL: "https://api.splunk.com/2.0/rest/login/splunk"}}
+	assert.Nil(l.Run(c))
+	assert.Empty(replayer.Unused())
+}
+
+func TestOpaqueStackToken(t *testing.T) {
+	fake, srv := acstest.NewServer()
+	defer srv.Close()
+	fake.SetToken("opaque-token")
+
+	g := &get{StackName: "test-stack", stackFlags: stackFlags{StackToken: "opaque-token", AcsURL: srv.URL}}
+	assert.Nil(t, g.Run(&context{NonInteractive: true}))
+	assert.Equal(t, 1, fake.Requests())
+}
@@ -18,52 +18,34 @@ import (
 	"encoding/json"
 	"fmt"
 
-	"github.com/AlecAivazis/survey/v2"
-	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/pipeline"
 )
 
 type get struct {
-	StackName  string `kong:"arg,help='the splunk cloud stack'"`
-	AppName    string `kong:"arg,optional,help='the app'"`
-	StackToken string `kong:"env='STACK_TOKEN',help='the stack sc_admin jwt token'"`
-	AcsURL     string `kong:"env='ACS_URL',help='the acs url',default='https://admin.splunk.com'"`
-	Victoria   bool   `kong:"help='whether the stack is a Victora stack'"`
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	AppName   string `kong:"arg,optional,help='the app'"`
+	stackFlags
 }
 
 func (g *get) Run(c *context) error {
 
-	if g.StackToken == "" {
-		survey.AskOne(&survey.Password{
-			Message: "stack token:",
-		}, &g.StackToken)
-		fmt.Println("")
+	cli, err := g.acsClient(c, g.StackName)
+	if err != nil {
+		return err
 	}
-
-	var cli acs.Client
-	if g.Victoria {
-		cli = acs.NewVictoriaWithURL(g.AcsURL, g.StackToken)
-	} else {
-		cli = acs.NewClassicWithURL(g.AcsURL, g.StackToken)
-	}
-
-	var object interface{}
-	var err error
-	if g.AppName == "" {
-		object, err = cli.ListApps(g.StackName)
-		if err != nil {
-			return err
-		}
-	} else {
-		object, err = cli.DescribeApp(g.StackName, g.AppName)
-		if err != nil {
-			return err
-		}
+	res, err := pipeline.Get(c.ctx(), pipeline.GetOptions{Client: cli, Stack: g.StackName, App: g.AppName})
+	if err != nil {
+		return err
 	}
+	printJSON(res.Object())
+	return nil
+}
 
+// printJSON pretty prints an api object to stdout
+func printJSON(object interface{}) {
 	if data, e := json.MarshalIndent(object, "", "    "); e == nil {
 		fmt.Printf("%s\n", string(data))
 	} else {
-		fmt.Printf("%v\n", data)
+		fmt.Printf("%v\n", object)
 	}
-	return nil
 }
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acsmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// captureStdout returns what run printed to stdout
+func captureStdout(t *testing.T, run func()) string {
+	r, w, err := os.Pipe()
+	assert.Nil(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	printed := make(chan []byte)
+	go func() {
+		data, _ := ioutil.ReadAll(r)
+		printed <- data
+	}()
+	run()
+	w.Close()
+	return string(<-printed)
+}
+
+func TestGet(t *testing.T) {
+	assert := assert.New(t)
+	name := "testapp"
+	cli := &acsmock.Client{}
+	cli.On("ListApps", "test-stack").Return([]acs.App{{Name: &name, Status: "installed"}}, nil)
+	cli.On("DescribeApp", "test-stack", "testapp").Return(&acs.App{Name: &name, Status: "installed"}, nil)
+
+	out := captureStdout(t, func() {
+		assert.Nil((&get{StackName: "test-stack"}).Run(&context{ACS: cli}))
+	})
+	assert.JSONEq(`[{"name": "testapp", "status": "installed"}]`, out)
+	out = captureStdout(t, func() {
+		assert.Nil((&get{StackName: "test-stack", AppName: "testapp"}).Run(&context{ACS: cli}))
+	})
+	assert.JSONEq(`{"name": "testapp", "status": "installed"}`, out)
+	cli.AssertExpectations(t)
+}
+
+func TestGetMissingApp(t *testing.T) {
+	cli := &acsmock.Client{}
+	cli.On("DescribeApp", "test-stack", "missing").Return(nil, errors.New("404 Not Found"))
+
+	out := captureStdout(t, func() {
+		assert.EqualError(t, (&get{StackName: "test-stack", AppName: "missing"}).Run(&context{ACS: cli}), "404 Not Found")
+	})
+	assert.Empty(t, out)
+	cli.AssertExpectations(t)
+}
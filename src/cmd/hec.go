@@ -0,0 +1,176 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+)
+
+type hec struct {
+	Create hecCreate `kong:"cmd,help='create an http event collector token on the splunk stack'"`
+	List   hecList   `kong:"cmd,help='list the http event collector tokens on the splunk stack'"`
+	Get    hecGet    `kong:"cmd,help='get an http event collector token on the splunk stack'"`
+	Update hecUpdate `kong:"cmd,help='update an http event collector token on the splunk stack'"`
+	Delete hecDelete `kong:"cmd,help='delete an http event collector token from the splunk stack'"`
+}
+
+type hecCreate struct {
+	StackName         string   `kong:"arg,help='the splunk cloud stack'"`
+	TokenName         string   `kong:"arg,help='the hec token'"`
+	DefaultIndex      string   `kong:"help='the index events are sent to when they do not specify one'"`
+	AllowedIndexes    []string `kong:"help='comma separated list of the indexes events can be sent to'"`
+	DefaultSourcetype string   `kong:"help='the sourcetype of events which do not specify one'"`
+	DefaultSource     string   `kong:"help='the source of events which do not specify one'"`
+	DefaultHost       string   `kong:"help='the host of events which do not specify one'"`
+	Disabled          bool     `kong:"help='create the token disabled'"`
+	UseAck            bool     `kong:"help='enable indexer acknowledgement for the token'"`
+	PrintToken        bool     `kong:"help='print the token value, it is not shown by any other command'"`
+	stackFlags
+}
+
+func (h *hecCreate) Run(c *context) error {
+	cli, err := h.acsClient(c, h.StackName)
+	if err != nil {
+		return err
+	}
+	token, err := cli.CreateHECToken(h.StackName, acs.HECTokenSpec{
+		Name:              h.TokenName,
+		DefaultIndex:      h.DefaultIndex,
+		AllowedIndexes:    h.AllowedIndexes,
+		DefaultSourcetype: h.DefaultSourcetype,
+		DefaultSource:     h.DefaultSource,
+		DefaultHost:       h.DefaultHost,
+		Disabled:          h.Disabled,
+		UseAck:            h.UseAck,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("created hec token '%s'\n", h.TokenName)
+	if h.PrintToken {
+		fmt.Printf("Token: %s\n", token.Token)
+	}
+	return nil
+}
+
+type hecList struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	stackFlags
+}
+
+func (h *hecList) Run(c *context) error {
+	cli, err := h.acsClient(c, h.StackName)
+	if err != nil {
+		return err
+	}
+	tokens, err := cli.ListHECTokens(h.StackName)
+	if err != nil {
+		return err
+	}
+	for i := range tokens {
+		tokens[i].Token = ""
+	}
+	printJSON(tokens)
+	return nil
+}
+
+type hecGet struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	TokenName string `kong:"arg,help='the hec token'"`
+	stackFlags
+}
+
+func (h *hecGet) Run(c *context) error {
+	cli, err := h.acsClient(c, h.StackName)
+	if err != nil {
+		return err
+	}
+	token, err := cli.DescribeHECToken(h.StackName, h.TokenName)
+	if err != nil {
+		return err
+	}
+	token.Token = ""
+	printJSON(token)
+	return nil
+}
+
+type hecUpdate struct {
+	StackName         string       `kong:"arg,help='the splunk cloud stack'"`
+	TokenName         string       `kong:"arg,help='the hec token'"`
+	DefaultIndex      string       `kong:"help='the index events are sent to when they do not specify one'"`
+	AllowedIndexes    []string     `kong:"help='comma separated list of the indexes events can be sent to'"`
+	DefaultSourcetype string       `kong:"help='the sourcetype of events which do not specify one'"`
+	DefaultSource     string       `kong:"help='the source of events which do not specify one'"`
+	DefaultHost       string       `kong:"help='the host of events which do not specify one'"`
+	Disabled          optionalBool `kong:"placeholder='BOOL',help='whether the token is disabled'"`
+	UseAck            optionalBool `kong:"placeholder='BOOL',help='whether indexer acknowledgement is enabled for the token'"`
+	stackFlags
+}
+
+func (h *hecUpdate) Run(c *context) error {
+	cli, err := h.acsClient(c, h.StackName)
+	if err != nil {
+		return err
+	}
+	// the update replaces the whole configuration, so start from the current one
+	token, err := cli.DescribeHECToken(h.StackName, h.TokenName)
+	if err != nil {
+		return err
+	}
+	spec := token.Spec
+	if h.DefaultIndex != "" {
+		spec.DefaultIndex = h.DefaultIndex
+	}
+	if h.AllowedIndexes != nil {
+		spec.AllowedIndexes = h.AllowedIndexes
+	}
+	if h.DefaultSourcetype != "" {
+		spec.DefaultSourcetype = h.DefaultSourcetype
+	}
+	if h.DefaultSource != "" {
+		spec.DefaultSource = h.DefaultSource
+	}
+	if h.DefaultHost != "" {
+		spec.DefaultHost = h.DefaultHost
+	}
+	if h.Disabled.value != nil {
+		spec.Disabled = *h.Disabled.value
+	}
+	if h.UseAck.value != nil {
+		spec.UseAck = *h.UseAck.value
+	}
+	err = cli.UpdateHECToken(h.StackName, h.TokenName, spec)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("updated hec token '%s'\n", h.TokenName)
+	return nil
+}
+
+type hecDelete struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	TokenName string `kong:"arg,help='the hec token'"`
+	stackFlags
+}
+
+func (h *hecDelete) Run(c *context) error {
+	cli, err := h.acsClient(c, h.StackName)
+	if err != nil {
+		return err
+	}
+	return cli.DeleteHECToken(h.StackName, h.TokenName)
+}
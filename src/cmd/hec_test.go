@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acsmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHECCreate(t *testing.T) {
+	assert := assert.New(t)
+	spec := acs.HECTokenSpec{Name: "ci", DefaultIndex: "main", AllowedIndexes: []string{"main"}, UseAck: true}
+	cli := &acsmock.Client{}
+	cli.On("CreateHECToken", "test-stack", spec).Return(&acs.HECToken{Spec: spec, Token: "secret"}, nil)
+
+	h := &hecCreate{StackName: "test-stack", TokenName: "ci", DefaultIndex: "main", AllowedIndexes: []string{"main"},
+		UseAck: true}
+	out := captureStdout(t, func() { assert.Nil(h.Run(&context{ACS: cli})) })
+	assert.Equal("created hec token 'ci'\n", out)
+
+	// the token value is only shown when asked for
+	h.PrintToken = true
+	out = captureStdout(t, func() { assert.Nil(h.Run(&context{ACS: cli})) })
+	assert.Equal("created hec token 'ci'\nToken: secret\n", out)
+	cli.AssertExpectations(t)
+}
+
+func TestHECListGetHideToken(t *testing.T) {
+	assert := assert.New(t)
+	token := acs.HECToken{Spec: acs.HECTokenSpec{Name: "ci", DefaultIndex: "main"}, Token: "secret"}
+	listed := token
+	cli := &acsmock.Client{}
+	cli.On("ListHECTokens", "test-stack").Return([]acs.HECToken{listed}, nil)
+	cli.On("DescribeHECToken", "test-stack", "ci").Return(&token, nil)
+
+	out := captureStdout(t, func() { assert.Nil((&hecList{StackName: "test-stack"}).Run(&context{ACS: cli})) })
+	assert.JSONEq(`[{"spec": {"name": "ci", "defaultIndex": "main", "disabled": false, "useAck": false}}]`, out)
+	out = captureStdout(t, func() {
+		assert.Nil((&hecGet{StackName: "test-stack", TokenName: "ci"}).Run(&context{ACS: cli}))
+	})
+	assert.JSONEq(`{"spec": {"name": "ci", "defaultIndex": "main", "disabled": false, "useAck": false}}`, out)
+	cli.AssertExpectations(t)
+}
+
+func TestHECUpdate(t *testing.T) {
+	assert := assert.New(t)
+	current := acs.HECTokenSpec{Name: "ci", DefaultIndex: "main", DefaultSource: "ci"}
+	disabled := true
+	updated := current
+	updated.DefaultIndex = "summary"
+	updated.Disabled = true
+
+	cli := &acsmock.Client{}
+	cli.On("DescribeHECToken", "test-stack", "ci").Return(&acs.HECToken{Spec: current}, nil)
+	cli.On("UpdateHECToken", "test-stack", "ci", updated).Return(nil)
+
+	// the flags which are not passed keep their current value
+	h := &hecUpdate{StackName: "test-stack", TokenName: "ci", DefaultIndex: "summary",
+		Disabled: optionalBool{value: &disabled}}
+	out := captureStdout(t, func() { assert.Nil(h.Run(&context{ACS: cli})) })
+	assert.Equal("updated hec token 'ci'\n", out)
+	cli.AssertExpectations(t)
+}
+
+func TestHECUpdateMissing(t *testing.T) {
+	cli := &acsmock.Client{}
+	cli.On("DescribeHECToken", "test-stack", "missing").Return(nil, errors.New("404 Not Found"))
+
+	h := &hecUpdate{StackName: "test-stack", TokenName: "missing", DefaultIndex: "summary"}
+	assert.EqualError(t, h.Run(&context{ACS: cli}), "404 Not Found")
+	cli.AssertExpectations(t)
+}
+
+func TestHECDelete(t *testing.T) {
+	cli := &acsmock.Client{}
+	cli.On("DeleteHECToken", "test-stack", "ci").Return(nil)
+
+	assert.Nil(t, (&hecDelete{StackName: "test-stack", TokenName: "ci"}).Run(&context{ACS: cli}))
+	cli.AssertExpectations(t)
+}
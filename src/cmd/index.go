@@ -0,0 +1,142 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+)
+
+type index struct {
+	Create indexCreate `kong:"cmd,help='create an index on the splunk stack'"`
+	List   indexList   `kong:"cmd,help='list the indexes on the splunk stack'"`
+	Get    indexGet    `kong:"cmd,help='get an index on the splunk stack'"`
+	Update indexUpdate `kong:"cmd,help='update an index on the splunk stack'"`
+	Delete indexDelete `kong:"cmd,help='delete an index from the splunk stack'"`
+}
+
+// indexSettingsFlags are the flags for the settings which can be changed after an index was created
+type indexSettingsFlags struct {
+	SearchableDays              optionalInt `kong:"placeholder='INT',help='the number of days the data is searchable'"`
+	MaxDataSizeMB               optionalInt `kong:"name='max-data-size-mb',placeholder='INT',help='the maximum size of the index in MB, 0 means unlimited'"`
+	SplunkArchivalRetentionDays optionalInt `kong:"placeholder='INT',help='the number of days the data is kept in the splunk archive after it is no longer searchable'"`
+	SelfStorageBucketPath       string      `kong:"help='the self storage bucket the data is archived to after it is no longer searchable'"`
+}
+
+func (f *indexSettingsFlags) settings() acs.IndexSettings {
+	return acs.IndexSettings{
+		SearchableDays:              f.SearchableDays.value,
+		MaxDataSizeMB:               f.MaxDataSizeMB.value,
+		SplunkArchivalRetentionDays: f.SplunkArchivalRetentionDays.value,
+		SelfStorageBucketPath:       optionalString(f.SelfStorageBucketPath),
+	}
+}
+
+type indexCreate struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	IndexName string `kong:"arg,help='the index'"`
+	Datatype  string `kong:"enum='event,metric',default='event',help='the type of data stored in the index (event or metric)'"`
+	indexSettingsFlags
+	stackFlags
+}
+
+func (i *indexCreate) Run(c *context) error {
+	cli, err := i.acsClient(c, i.StackName)
+	if err != nil {
+		return err
+	}
+	err = cli.CreateIndex(i.StackName, acs.Index{
+		Name:          i.IndexName,
+		Datatype:      i.Datatype,
+		IndexSettings: i.settings(),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("created index '%s'\n", i.IndexName)
+	return nil
+}
+
+type indexList struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	stackFlags
+}
+
+func (i *indexList) Run(c *context) error {
+	cli, err := i.acsClient(c, i.StackName)
+	if err != nil {
+		return err
+	}
+	indexes, err := cli.ListIndexes(i.StackName)
+	if err != nil {
+		return err
+	}
+	printJSON(indexes)
+	return nil
+}
+
+type indexGet struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	IndexName string `kong:"arg,help='the index'"`
+	stackFlags
+}
+
+func (i *indexGet) Run(c *context) error {
+	cli, err := i.acsClient(c, i.StackName)
+	if err != nil {
+		return err
+	}
+	idx, err := cli.DescribeIndex(i.StackName, i.IndexName)
+	if err != nil {
+		return err
+	}
+	printJSON(idx)
+	return nil
+}
+
+type indexUpdate struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	IndexName string `kong:"arg,help='the index'"`
+	indexSettingsFlags
+	stackFlags
+}
+
+func (i *indexUpdate) Run(c *context) error {
+	cli, err := i.acsClient(c, i.StackName)
+	if err != nil {
+		return err
+	}
+	err = cli.UpdateIndex(i.StackName, i.IndexName, i.settings())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("updated index '%s'\n", i.IndexName)
+	return nil
+}
+
+type indexDelete struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	IndexName string `kong:"arg,help='the index'"`
+	stackFlags
+}
+
+func (i *indexDelete) Run(c *context) error {
+	cli, err := i.acsClient(c, i.StackName)
+	if err != nil {
+		return err
+	}
+	return cli.DeleteIndex(i.StackName, i.IndexName)
+}
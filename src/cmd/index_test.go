@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acsmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexCreate(t *testing.T) {
+	days := 90
+	index := acs.Index{Name: "web", Datatype: "event", IndexSettings: acs.IndexSettings{SearchableDays: &days}}
+	cli := &acsmock.Client{}
+	cli.On("CreateIndex", "test-stack", index).Return(nil)
+
+	i := &indexCreate{StackName: "test-stack", IndexName: "web", Datatype: "event",
+		indexSettingsFlags: indexSettingsFlags{SearchableDays: optionalInt{value: &days}}}
+	out := captureStdout(t, func() { assert.Nil(t, i.Run(&context{ACS: cli})) })
+	assert.Equal(t, "created index 'web'\n", out)
+	cli.AssertExpectations(t)
+}
+
+func TestIndexListGetUpdateDelete(t *testing.T) {
+	assert := assert.New(t)
+	days := 90
+	path := "s3://bucket"
+	index := acs.Index{Name: "web", Datatype: "event", IndexSettings: acs.IndexSettings{SearchableDays: &days}}
+	cli := &acsmock.Client{}
+	cli.On("ListIndexes", "test-stack").Return([]acs.Index{index}, nil)
+	cli.On("DescribeIndex", "test-stack", "web").Return(&index, nil)
+	cli.On("UpdateIndex", "test-stack", "web", acs.IndexSettings{SelfStorageBucketPath: &path}).Return(nil)
+	cli.On("DeleteIndex", "test-stack", "web").Return(nil)
+
+	out := captureStdout(t, func() { assert.Nil((&indexList{StackName: "test-stack"}).Run(&context{ACS: cli})) })
+	assert.JSONEq(`[{"name": "web", "datatype": "event", "searchableDays": 90}]`, out)
+	out = captureStdout(t, func() {
+		assert.Nil((&indexGet{StackName: "test-stack", IndexName: "web"}).Run(&context{ACS: cli}))
+	})
+	assert.JSONEq(`{"name": "web", "datatype": "event", "searchableDays": 90}`, out)
+	update := &indexUpdate{StackName: "test-stack", IndexName: "web",
+		indexSettingsFlags: indexSettingsFlags{SelfStorageBucketPath: path}}
+	out = captureStdout(t, func() { assert.Nil(update.Run(&context{ACS: cli})) })
+	assert.Equal("updated index 'web'\n", out)
+	assert.Nil((&indexDelete{StackName: "test-stack", IndexName: "web"}).Run(&context{ACS: cli}))
+	cli.AssertExpectations(t)
+}
@@ -15,85 +15,78 @@
 package main
 
 import (
-	"bytes"
 	"fmt"
-	"github.com/AlecAivazis/survey/v2"
+	"strings"
+	"time"
+
 	"github.com/splunk/acs-privateapps-demo/src/acs"
-	"github.com/splunk/acs-privateapps-demo/src/appinspect"
-	"io/ioutil"
-	"path/filepath"
+	"github.com/splunk/acs-privateapps-demo/src/pipeline"
 )
 
 type install struct {
-	StackName         string `kong:"arg,help='the splunk cloud stack'"`
-	SplunkComUsername string `kong:"env='SPLUNK_COM_USERNAME',help='the splunkbase username'"`
-	SplunkComPassword string `kong:"env='SPLUNK_COM_PASSWORD',help='the splunkbase password'"`
-	PackageFilePath   string `kong:"arg,help='the path to the app-package (tar.gz) file',type='path'"`
-	StackToken        string `kong:"env='STACK_TOKEN',help='the stack sc_admin jwt token'"`
-	AcsURL            string `kong:"env='ACS_URL',help='the acs url',default='https://admin.splunk.com'"`
-	Victoria          bool   `kong:"help='whether the stack is a Victora stack'"`
+	StackName       string        `kong:"arg,help='the splunk cloud stack'"`
+	PackageFilePath string        `kong:"arg,help='the path to the app-package (tar.gz) file',type='path'"`
+	RestartIfNeeded bool          `kong:"help='restart the stack if the install requires it and wait for it to come back'"`
+	RestartTimeout  time.Duration `kong:"default='30m',help='how long to wait for the stack to restart'"`
+	MaxPackageSize  int64         `kong:"default='${maxPackageSize}',help='the largest app package in MB uploaded to a victoria stack, 0 for no limit'"`
+	Indexes         []string      `kong:"name='index',placeholder='NAME[:DATATYPE]',help='create the index the app needs before installing it unless the stack has it, the datatype is event (default) or metric, repeatable'"`
+	maintenanceFlags
+	splunkComFlags
+	stackFlags
 }
 
 func (i *install) Run(c *context) error {
-
-	pf, err := ioutil.ReadFile(i.PackageFilePath)
+	indexes, err := parseIndexes(i.Indexes)
 	if err != nil {
 		return err
 	}
-	if i.SplunkComUsername == "" {
-		survey.AskOne(&survey.Input{
-			Message: "splunkbase username:",
-		}, &i.SplunkComUsername)
-	}
-	if i.SplunkComPassword == "" {
-		survey.AskOne(&survey.Password{
-			Message: "splunkbase password:",
-		}, &i.SplunkComPassword)
-		fmt.Println("")
+	// ask for every missing credential before anything is uploaded
+	if err = c.ask(append(i.tokenPrompts(c), i.credentialPrompts(c)...)...); err != nil {
+		return err
 	}
-
-	if i.StackToken == "" {
-		survey.AskOne(&survey.Password{
-			Message: "stack token:",
-		}, &i.StackToken)
-		fmt.Println("")
+	pkg, err := openPackage(i.PackageFilePath)
+	if err != nil {
+		return err
 	}
-	ar, err := appinspect.Authenticate(i.SplunkComUsername, i.SplunkComPassword)
+	defer pkg.Close()
+	cli, err := i.acsClient(c, i.StackName, acs.WithMaxPackageSize(i.MaxPackageSize<<20))
 	if err != nil {
 		return err
 	}
-
-	var cli acs.Client
-	if i.Victoria {
-		cli = acs.NewVictoriaWithURL(i.AcsURL, i.StackToken)
-	} else {
-		cli = acs.NewClassicWithURL(i.AcsURL, i.StackToken)
+	credentials, err := i.credentials(c)
+	if err != nil {
+		return err
 	}
-	return cli.InstallApp(i.StackName, ar.Data.Token, filepath.Base(i.PackageFilePath), bytes.NewReader(pf))
-}
-
-type uninstall struct {
-	StackName  string `kong:"arg,help='the splunk cloud stack'"`
-	AppName    string `kong:"arg,optional,help='the app'"`
-	StackToken string `kong:"env='STACK_TOKEN',help='the stack sc_admin jwt token'"`
-	AcsURL     string `kong:"env='ACS_URL',help='the acs url',default='https://admin.splunk.com'"`
-	Victoria   bool   `kong:"help='whether the stack is a Victora stack'"`
+	_, err = pipeline.Install(c.ctx(), pipeline.InstallOptions{
+		Client:          cli,
+		Stack:           i.StackName,
+		Package:         pkg,
+		Authenticator:   i.authenticator(c),
+		Credentials:     credentials,
+		RestartIfNeeded: i.RestartIfNeeded,
+		RestartTimeout:  i.RestartTimeout,
+		Indexes:         indexes,
+		Maintenance:     i.options(),
+		Logger:          c.Logger,
+		Tracer:          c.Tracer,
+		Out:             c.progress(),
+	})
+	return maintenanceError(err)
 }
 
-func (u *uninstall) Run(c *context) error {
-
-	if u.StackToken == "" {
-		survey.AskOne(&survey.Password{
-			Message: "stack token:",
-		}, &u.StackToken)
-		fmt.Println("")
-	}
-
-	var cli acs.Client
-	if u.Victoria {
-		cli = acs.NewVictoriaWithURL(u.AcsURL, u.StackToken)
-	} else {
-		cli = acs.NewClassicWithURL(u.AcsURL, u.StackToken)
+// parseIndexes parses the indexes of --index, given as name or name:datatype
+func parseIndexes(flags []string) ([]acs.Index, error) {
+	var indexes []acs.Index
+	for _, flag := range flags {
+		parts := strings.SplitN(flag, ":", 2)
+		index := acs.Index{Name: parts[0], Datatype: "event"}
+		if len(parts) == 2 {
+			index.Datatype = parts[1]
+		}
+		if index.Name == "" || (index.Datatype != "event" && index.Datatype != "metric") {
+			return nil, fmt.Errorf("invalid index '%s', expected NAME or NAME:DATATYPE with a datatype of event or metric", flag)
+		}
+		indexes = append(indexes, index)
 	}
-	return cli.UninstallApp(u.StackName, u.AppName)
+	return indexes, nil
 }
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acsmock"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acstest"
+	"github.com/splunk/acs-privateapps-demo/src/appinspect/appinspecttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInstall(t *testing.T) {
+	assert := assert.New(t)
+	_, aiSrv := appinspecttest.NewServer()
+	defer aiSrv.Close()
+
+	for _, victoria := range []bool{false, true} {
+		newServer := acstest.NewServer
+		if victoria {
+			newServer = acstest.NewVictoriaServer
+		}
+		fake, acsSrv := newServer()
+		defer acsSrv.Close()
+		i := &install{
+			StackName:       "test-stack",
+			PackageFilePath: testPackage(t),
+			splunkComFlags:  testSplunkComFlags(aiSrv.URL),
+			stackFlags: stackFlags{
+				StackToken: testStackToken(),
+				AcsURL:     acsSrv.URL,
+				Victoria:   victoria,
+			},
+		}
+		assert.Nil(i.Run(&context{}))
+
+		apps := fake.Apps("test-stack")
+		assert.Len(apps, 1)
+		assert.Equal("testapp", *apps[0].Name)
+		assert.Equal("Private App 1", *apps[0].Label)
+		assert.Equal("1.0.0", *apps[0].Version)
+	}
+}
+
+func TestInstallExpiredToken(t *testing.T) {
+	fake, acsSrv := acstest.NewServer()
+	defer acsSrv.Close()
+
+	i := &install{
+		StackName:       "test-stack",
+		PackageFilePath: testPackage(t),
+		splunkComFlags:  testSplunkComFlags(acsSrv.URL),
+		stackFlags: stackFlags{
+			StackToken: "eyJhbGciOiJub25lIn0.eyJleHAiOjF9.",
+			AcsURL:     acsSrv.URL,
+		},
+	}
+	err := i.Run(&context{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "expired")
+	assert.Equal(t, 0, fake.Requests())
+}
+
+func TestInstallRestart(t *testing.T) {
+	cli := &acsmock.Client{}
+	auth := testAuthenticator("token")
+	cli.On("ListMaintenanceWindows", "test-stack").Return([]acs.MaintenanceWindow{}, nil)
+	cli.On("InstallApp", "test-stack", "token", "app-package.tar.gz", mock.Anything).Return(nil)
+	cli.On("RestartRequired", "test-stack").Return(true, nil)
+	cli.On("RestartStack", "test-stack").Return(nil)
+	cli.On("WaitForRestart", mock.Anything, "test-stack", time.Minute).Return(nil)
+
+	i := &install{
+		StackName:       "test-stack",
+		PackageFilePath: testPackage(t),
+		RestartIfNeeded: true,
+		RestartTimeout:  time.Minute,
+		splunkComFlags:  splunkComFlags{SplunkComUsername: "user", SplunkComPassword: "pass"},
+	}
+	assert.Nil(t, i.Run(&context{ACS: cli, Authenticator: auth}))
+	cli.AssertExpectations(t)
+	auth.AssertExpectations(t)
+}
+
+func TestInstallFailure(t *testing.T) {
+	cli := &acsmock.Client{}
+	auth := testAuthenticator("token")
+	cli.On("ListMaintenanceWindows", "test-stack").Return([]acs.MaintenanceWindow{}, nil)
+	cli.On("InstallApp", "test-stack", "token", "app-package.tar.gz", mock.Anything).
+		Return(errors.New("error while submit: 400 Bad Request"))
+
+	i := &install{
+		StackName:       "test-stack",
+		PackageFilePath: testPackage(t),
+		splunkComFlags:  splunkComFlags{SplunkComUsername: "user", SplunkComPassword: "pass"},
+	}
+	assert.EqualError(t, i.Run(&context{ACS: cli, Authenticator: auth}), "error while submit: 400 Bad Request")
+	cli.AssertNotCalled(t, "RestartRequired", "test-stack")
+	cli.AssertExpectations(t)
+}
+
+func TestInstallIndexes(t *testing.T) {
+	cli := &acsmock.Client{}
+	auth := testAuthenticator("token")
+	cli.On("ListMaintenanceWindows", "test-stack").Return([]acs.MaintenanceWindow{}, nil)
+	cli.On("ListIndexes", "test-stack").Return([]acs.Index{{Name: "web", Datatype: "event"}}, nil)
+	cli.On("CreateIndex", "test-stack", acs.Index{Name: "web_metrics", Datatype: "metric"}).Return(nil)
+	cli.On("InstallApp", "test-stack", "token", "app-package.tar.gz", mock.Anything).Return(nil)
+	cli.On("RestartRequired", "test-stack").Return(false, nil)
+
+	// the existing indexes are left as they are
+	i := &install{
+		StackName:       "test-stack",
+		PackageFilePath: testPackage(t),
+		Indexes:         []string{"web", "web_metrics:metric"},
+		splunkComFlags:  splunkComFlags{SplunkComUsername: "user", SplunkComPassword: "pass"},
+	}
+	assert.Nil(t, i.Run(&context{ACS: cli, Authenticator: auth}))
+	cli.AssertNotCalled(t, "CreateIndex", "test-stack", acs.Index{Name: "web", Datatype: "event"})
+	cli.AssertExpectations(t)
+}
+
+func TestParseIndexes(t *testing.T) {
+	assert := assert.New(t)
+	indexes, err := parseIndexes([]string{"web", "web_metrics:metric"})
+	assert.Nil(err)
+	assert.Equal([]acs.Index{{Name: "web", Datatype: "event"}, {Name: "web_metrics", Datatype: "metric"}}, indexes)
+
+	for _, invalid := range []string{"", ":event", "web:logs"} {
+		_, err = parseIndexes([]string{invalid})
+		assert.EqualError(err, fmt.Sprintf("invalid index '%s', expected NAME or NAME:DATATYPE with a datatype of event or metric", invalid))
+	}
+}
+
+func TestInstallProgress(t *testing.T) {
+	assert := assert.New(t)
+	logger, err := newLogger("warn", "text", false)
+	assert.Nil(err)
+	cli := &acsmock.Client{}
+	cli.On("ListMaintenanceWindows", "test-stack").Return([]acs.MaintenanceWindow{}, nil)
+	cli.On("ListIndexes", "test-stack").Return([]acs.Index{}, nil)
+	cli.On("CreateIndex", "test-stack", acs.Index{Name: "web", Datatype: "event"}).Return(nil)
+	cli.On("InstallApp", "test-stack", "token", "app-package.tar.gz", mock.Anything).Return(nil)
+	cli.On("RestartRequired", "test-stack").Return(true, nil)
+	cli.On("RestartStack", "test-stack").Return(nil)
+	cli.On("WaitForRestart", mock.Anything, "test-stack", time.Minute).Return(nil)
+
+	// the progress is printed with the default log level
+	i := &install{
+		StackName:       "test-stack",
+		PackageFilePath: testPackage(t),
+		Indexes:         []string{"web"},
+		RestartTimeout:  time.Minute,
+		splunkComFlags:  splunkComFlags{SplunkComUsername: "user", SplunkComPassword: "pass"},
+	}
+	c := &context{ACS: cli, Authenticator: testAuthenticator("token"), Logger: logger}
+	out := captureStdout(t, func() { assert.Nil(i.Run(c)) })
+	assert.Equal("created index 'web'\napp installed, the stack requires a restart for it to take effect\n", out)
+
+	i.Indexes = nil
+	i.RestartIfNeeded = true
+	out = captureStdout(t, func() { assert.Nil(i.Run(c)) })
+	assert.Equal("app installed, restarting the stack...\nstack restarted\n", out)
+	cli.AssertExpectations(t)
+}
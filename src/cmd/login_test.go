@@ -0,0 +1,24 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/appinspect"
+	"github.com/splunk/acs-privateapps-demo/src/appinspect/appinspectmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogin(t *testing.T) {
+	res := &appinspect.AuthenticateResult{}
+	res.Data.Token = "token"
+	auth := &appinspectmock.Authenticator{}
+	auth.On("Authenticate", "user", "pass").Return(res, nil)
+	auth.On("Authenticate", "user", "wrong").Return(nil, errors.New("401 Unauthorized"))
+
+	l := &login{splunkComFlags: splunkComFlags{SplunkComUsername: "user", SplunkComPassword: "pass"}}
+	assert.Nil(t, l.Run(&context{Authenticator: auth}))
+	l.SplunkComPassword = "wrong"
+	assert.EqualError(t, l.Run(&context{Authenticator: auth}), "401 Unauthorized")
+	auth.AssertExpectations(t)
+}
@@ -15,25 +15,226 @@
 package main
 
 import (
+	gocontext "context"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
 	"github.com/alecthomas/kong"
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/appinspect"
+	"github.com/splunk/acs-privateapps-demo/src/cassette"
+	"github.com/splunk/acs-privateapps-demo/src/httpclient"
+	"github.com/splunk/acs-privateapps-demo/src/logging"
+	"github.com/splunk/acs-privateapps-demo/src/telemetry"
 )
 
 type context struct {
+	// NonInteractive fails listing the missing credentials instead of prompting for them
+	NonInteractive bool
+	// Debug traces the requests of the acs and appinspect clients to stderr, the logger logs at the debug
+	// level then
 	Debug bool
+	// Transport is used by the acs and appinspect clients when set, i.e. to record or replay a session
+	Transport http.RoundTripper
+	// Tracer records the spans and metrics of the command and of the acs and appinspect calls, nil records
+	// nothing
+	Tracer *telemetry.Tracer
+	// Logger writes the logs of the commands and of the requests of the acs and appinspect clients, nil
+	// logs nothing
+	Logger *logging.Logger
+	// Ctx is done when the command is interrupted, which stops its waits, nil is never done
+	Ctx gocontext.Context
+
+	// ACS, AppInspect and Authenticator are used by the commands instead of the clients built from their
+	// flags when set, i.e. to run the commands against mocks
+	ACS           acs.Client
+	AppInspect    appinspect.ClientInterface
+	Authenticator appinspect.Authenticator
+}
+
+// ctx returns the context the waits of the commands stop with
+func (c *context) ctx() gocontext.Context {
+	if c.Ctx == nil {
+		return gocontext.Background()
+	}
+	return c.Ctx
+}
+
+// progress returns where the pipeline steps write their progress messages, the logger only gets diagnostics
+func (c *context) progress() io.Writer {
+	return os.Stdout
+}
+
+// replaying reports whether the commands replay a cassette, whose tokens and passwords were redacted
+func (c *context) replaying() bool {
+	_, ok := c.Transport.(*cassette.Replayer)
+	return ok
+}
+
+// httpOptions returns the http options shared by the acs and appinspect clients created by the commands
+func (c *context) httpOptions() []httpclient.Option {
+	var opts []httpclient.Option
+	if c.Transport != nil {
+		opts = append(opts, httpclient.WithTransport(c.Transport))
+	}
+	if c.Logger != nil {
+		opts = append(opts, httpclient.WithLogger(c.Logger))
+	}
+	if c.Debug {
+		opts = append(opts, httpclient.WithRequestHook(tracer.Request), httpclient.WithResponseHook(tracer.Response))
+	}
+	return opts
+}
+
+// acsOptions returns the options of the acs clients created by the commands
+func (c *context) acsOptions() []acs.Option {
+	return []acs.Option{acs.WithHTTP(c.httpOptions()...)}
 }
 
+// appinspectOptions returns the options of the appinspect clients created by the commands
+func (c *context) appinspectOptions() []appinspect.Option {
+	return []appinspect.Option{appinspect.WithHTTP(c.httpOptions()...)}
+}
+
+// tracer writes the --debug traces
+var tracer = httpclient.NewTracer(os.Stderr)
+
 var cli struct {
-	Debug     bool      `kong:"help='enable debug mode'"`
-	Login     login     `kong:"cmd,help='login to splunkbase and generate token'"`
-	Vet       vet       `kong:"cmd,help='vet the app package against the app-inspect service'"`
-	Install   install   `kong:"cmd,help=install the app package on the splunk stack"`
-	Uninstall uninstall `kong:"cmd,help=uninstall the app package from the splunk stack"`
-	Get       get       `kong:"cmd,help=get an app/apps installed on the splunk stack"`
+	NonInteractive bool   `kong:"help='never prompt, fail listing the missing credentials instead, on by default when stdin is not a terminal'"`
+	Debug          bool   `kong:"help='trace the requests to the services, with secrets redacted, to stderr, and log at the debug level'"`
+	LogLevel       string `kong:"default='warn',enum='${logLevels}',help='the level of the logs written to stderr, one of ${logLevels}'"`
+	LogFormat      string `kong:"default='text',enum='${logFormats}',help='the format of the logs, one of ${logFormats}'"`
+	TraceFile      string `kong:"type='path',help='write the spans and metrics of the command to a json file, i.e. as a ci artifact'"`
+	OTLPEndpoint   string `kong:"name='otlp-endpoint',env='OTEL_EXPORTER_OTLP_ENDPOINT',help='export the spans and metrics of the command to the OTLP/HTTP collector at this url'"`
+	transportFlags
+	Record        string        `kong:"xor='cassette',type='path',help='record the http interactions to a cassette file, with secrets redacted'"`
+	Replay        string        `kong:"xor='cassette',type='path',help='replay the http interactions of a cassette file instead of calling the services'"`
+	Login         login         `kong:"cmd,help='login to splunkbase and generate token'"`
+	Vet           vet           `kong:"cmd,help='vet the app package against the app-inspect service'"`
+	Install       install       `kong:"cmd,help=install the app package on the splunk stack"`
+	Uninstall     uninstall     `kong:"cmd,help=uninstall the app package from the splunk stack"`
+	Get           get           `kong:"cmd,help=get an app/apps installed on the splunk stack"`
+	Splunkbase    splunkbase    `kong:"cmd,help='manage the splunkbase apps installed on the splunk stack'"`
+	Index         index         `kong:"cmd,help='manage the indexes on the splunk stack'"`
+	Hec           hec           `kong:"cmd,help='manage the http event collector tokens on the splunk stack'"`
+	Allowlist     allowlist     `kong:"cmd,help='manage the ip allow lists of the splunk stack'"`
+	Status        status        `kong:"cmd,help='check that the splunk stack is ready to be deployed to'"`
+	Token         token         `kong:"cmd,help='manage the authentication tokens of the splunk stack'"`
+	OutboundPorts outboundPorts `kong:"cmd,help='manage the outbound ports of the splunk stack'"`
+	Role          role          `kong:"cmd,help='manage the roles on the splunk stack'"`
+	User          user          `kong:"cmd,help='manage the users on the splunk stack'"`
+	Maintenance   maintenance   `kong:"cmd,help='show the maintenance windows of the splunk stack'"`
+	API           api           `kong:"cmd,name='api',help='call any acs endpoint of the splunk stack'"`
+	FakeACS       fakeACS       `kong:"cmd,name='fake-acs',help='run a fake acs locally for testing'"`
 }
 
 func main() {
-	ctx := kong.Parse(&cli)
+	ctx := kong.Parse(&cli, kong.Vars{
+		"allowListFeatures": allowListFeatures,
+		"maxPackageSize":    strconv.Itoa(acs.DefaultMaxPackageSize >> 20),
+		"logLevels":         logging.Levels,
+		"logFormats":        logging.Formats,
+	})
+	logger, err := newLogger(cli.LogLevel, cli.LogFormat, cli.Debug)
+	ctx.FatalIfErrorf(err)
+	transport, err := cli.transport()
+	ctx.FatalIfErrorf(err)
+	interrupted, stop := interruptible()
+	defer stop()
+	c := &context{
+		NonInteractive: cli.NonInteractive || !isTerminal(os.Stdin),
+		Debug:          cli.Debug,
+		Transport:      transport,
+		Logger:         logger.With("command", ctx.Command()),
+		Ctx:            interrupted,
+	}
+	var recorder *cassette.Recorder
+	switch {
+	case cli.Record != "":
+		recorder = cassette.NewRecorder(transport)
+		c.Transport = recorder
+	case cli.Replay != "":
+		replayer, err := cassette.LoadReplayer(cli.Replay)
+		ctx.FatalIfErrorf(err)
+		c.Transport = replayer
+	}
+	if cli.TraceFile != "" || cli.OTLPEndpoint != "" {
+		c.Tracer = telemetry.NewTracer("cloudCtl")
+	}
 	// Call the Run() method of the selected parsed command.
-	err := ctx.Run(&context{Debug: cli.Debug})
+	start := time.Now()
+	c.Logger.Debug("command started")
+	span := c.Tracer.Start("command", "command", ctx.Command())
+	err = ctx.Run(c)
+	span.Finish(err)
+	exportTrace(c, transport, cli.TraceFile, cli.OTLPEndpoint)
+	if err != nil {
+		c.Logger.Debug("command failed", "error", err, "durationMs", time.Since(start).Milliseconds())
+	} else {
+		c.Logger.Debug("command succeeded", "durationMs", time.Since(start).Milliseconds())
+	}
+	if recorder != nil {
+		// failed sessions are recorded too, they are often the ones worth replaying
+		if e := recorder.Save(cli.Record); e != nil {
+			c.Logger.Error("failed to write cassette", "file", cli.Record, "error", e)
+		}
+	}
 	ctx.FatalIfErrorf(err)
 }
+
+// interruptible returns a context which is done once the process is interrupted or terminated, a second
+// interrupt kills the process as usual. stop releases the signals.
+func interruptible() (ctx gocontext.Context, stop func()) {
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			signal.Stop(signals)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel()
+	}
+}
+
+// exportTrace writes the trace of the command to the file and the collector given on the command line. The
+// collector is reached through transport rather than the one of the context, which may be replaying a session.
+func exportTrace(c *context, transport http.RoundTripper, file, endpoint string) {
+	if file != "" {
+		if err := c.Tracer.WriteFile(file); err != nil {
+			c.Logger.Error("failed to write trace", "file", file, "error", err)
+		}
+	}
+	if endpoint != "" {
+		if err := c.Tracer.ExportOTLP(&http.Client{Transport: transport, Timeout: 10 * time.Second}, endpoint); err != nil {
+			c.Logger.Error("failed to export trace", "endpoint", endpoint, "error", err)
+		}
+	}
+}
+
+// newLogger returns the logger writing to stderr at the level and in the format of the flags, --debug logs
+// at the debug level whatever the level
+func newLogger(level, format string, debug bool) (*logging.Logger, error) {
+	l, err := logging.ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	if debug {
+		l = logging.LevelDebug
+	}
+	f, err := logging.ParseFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	return logging.New(os.Stderr, l, f), nil
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLogger(t *testing.T) {
+	assert := assert.New(t)
+	logger, err := newLogger("warn", "text", false)
+	assert.Nil(err)
+	assert.False(logger.Enabled(logging.LevelInfo))
+
+	// --debug raises the level whatever the one of --log-level
+	logger, err = newLogger("warn", "text", true)
+	assert.Nil(err)
+	assert.True(logger.Enabled(logging.LevelDebug))
+
+	_, err = newLogger("verbose", "text", true)
+	assert.Error(err)
+}
@@ -0,0 +1,64 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/splunk/acs-privateapps-demo/src/pipeline"
+)
+
+type maintenance struct {
+	List maintenanceList `kong:"cmd,help='list the maintenance windows scheduled on the splunk stack'"`
+}
+
+type maintenanceList struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	stackFlags
+}
+
+func (m *maintenanceList) Run(c *context) error {
+	cli, err := m.acsClient(c, m.StackName)
+	if err != nil {
+		return err
+	}
+	windows, err := cli.ListMaintenanceWindows(m.StackName)
+	if err != nil {
+		return err
+	}
+	printJSON(windows)
+	return nil
+}
+
+// maintenanceFlags are shared by the commands which must not run during a maintenance window
+type maintenanceFlags struct {
+	IgnoreMaintenance bool          `kong:"help='run even if the stack is in a maintenance window'"`
+	MaintenanceWait   time.Duration `kong:"default='0s',help='how long to wait for an ongoing maintenance window to end, fails right away if 0'"`
+}
+
+func (m *maintenanceFlags) options() pipeline.MaintenanceOptions {
+	return pipeline.MaintenanceOptions{Ignore: m.IgnoreMaintenance, Wait: m.MaintenanceWait}
+}
+
+// maintenanceError points at --ignore-maintenance when err is a maintenance window
+func maintenanceError(err error) error {
+	var maintenance *pipeline.MaintenanceError
+	if errors.As(err, &maintenance) {
+		return fmt.Errorf("%s, use --ignore-maintenance to run anyway", err)
+	}
+	return err
+}
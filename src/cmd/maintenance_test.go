@@ -0,0 +1,21 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acsmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceList(t *testing.T) {
+	cli := &acsmock.Client{}
+	cli.On("ListMaintenanceWindows", "test-stack").Return([]acs.MaintenanceWindow{{ID: "1"}}, nil).Once()
+	cli.On("ListMaintenanceWindows", "test-stack").Return(nil, errors.New("503 Service Unavailable")).Once()
+
+	m := &maintenanceList{StackName: "test-stack"}
+	assert.Nil(t, m.Run(&context{ACS: cli}))
+	assert.EqualError(t, m.Run(&context{ACS: cli}), "503 Service Unavailable")
+	cli.AssertExpectations(t)
+}
@@ -0,0 +1,159 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+)
+
+type outboundPorts struct {
+	Create outboundPortsCreate `kong:"cmd,help='open an outbound port from the splunk stack to subnets'"`
+	List   outboundPortsList   `kong:"cmd,help='list the outbound ports of the splunk stack'"`
+	Get    outboundPortsGet    `kong:"cmd,help='get an outbound port of the splunk stack'"`
+	Delete outboundPortsDelete `kong:"cmd,help='close an outbound port from the splunk stack to subnets'"`
+	Apply  outboundPortsApply  `kong:"cmd,help='open the outbound ports declared in a file which are not open yet'"`
+}
+
+type outboundPortsCreate struct {
+	StackName string   `kong:"arg,help='the splunk cloud stack'"`
+	Port      int      `kong:"arg,help='the port'"`
+	Subnets   []string `kong:"arg,help='the subnets in CIDR notation'"`
+	Reason    string   `kong:"required,help='why the port needs to be opened'"`
+	stackFlags
+}
+
+func (o *outboundPortsCreate) Run(c *context) error {
+	cli, err := o.acsClient(c, o.StackName)
+	if err != nil {
+		return err
+	}
+	return cli.CreateOutboundPort(o.StackName, acs.OutboundPort{Port: o.Port, Subnets: o.Subnets}, o.Reason)
+}
+
+type outboundPortsList struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	stackFlags
+}
+
+func (o *outboundPortsList) Run(c *context) error {
+	cli, err := o.acsClient(c, o.StackName)
+	if err != nil {
+		return err
+	}
+	ports, err := cli.ListOutboundPorts(o.StackName)
+	if err != nil {
+		return err
+	}
+	printJSON(ports)
+	return nil
+}
+
+type outboundPortsGet struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	Port      int    `kong:"arg,help='the port'"`
+	stackFlags
+}
+
+func (o *outboundPortsGet) Run(c *context) error {
+	cli, err := o.acsClient(c, o.StackName)
+	if err != nil {
+		return err
+	}
+	port, err := cli.DescribeOutboundPort(o.StackName, o.Port)
+	if err != nil {
+		return err
+	}
+	printJSON(port)
+	return nil
+}
+
+type outboundPortsDelete struct {
+	StackName string   `kong:"arg,help='the splunk cloud stack'"`
+	Port      int      `kong:"arg,help='the port'"`
+	Subnets   []string `kong:"arg,help='the subnets in CIDR notation'"`
+	stackFlags
+}
+
+func (o *outboundPortsDelete) Run(c *context) error {
+	cli, err := o.acsClient(c, o.StackName)
+	if err != nil {
+		return err
+	}
+	return cli.DeleteOutboundPort(o.StackName, o.Port, o.Subnets)
+}
+
+type outboundPortsApply struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	PortsFile string `kong:"required,type='existingfile',help='json file with the list of outbound ports, e.g. [{\"port\": 443, \"subnets\": [\"10.0.0.0/24\"]}]'"`
+	Reason    string `kong:"required,help='why the ports need to be opened'"`
+	DryRun    bool   `kong:"help='only print the changes which would be made'"`
+	stackFlags
+}
+
+func (o *outboundPortsApply) Run(c *context) error {
+	data, err := ioutil.ReadFile(o.PortsFile)
+	if err != nil {
+		return err
+	}
+	var declared []acs.OutboundPort
+	if err = json.Unmarshal(data, &declared); err != nil {
+		return fmt.Errorf("failed to parse %s: %s", o.PortsFile, err)
+	}
+	for _, port := range declared {
+		if len(port.Subnets) == 0 {
+			return fmt.Errorf("port %d: no subnets given", port.Port)
+		}
+		if err = acs.ValidateSubnets(port.Subnets); err != nil {
+			return fmt.Errorf("port %d: %s", port.Port, err)
+		}
+	}
+
+	cli, err := o.acsClient(c, o.StackName)
+	if err != nil {
+		return err
+	}
+	current, err := cli.ListOutboundPorts(o.StackName)
+	if err != nil {
+		return err
+	}
+	open := map[int][]string{}
+	for _, port := range current {
+		open[port.Port] = append(open[port.Port], port.Subnets...)
+	}
+
+	for _, port := range declared {
+		toAdd, _, err := acs.DiffSubnets(open[port.Port], port.Subnets)
+		if err != nil {
+			return err
+		}
+		if len(toAdd) == 0 {
+			fmt.Printf("outbound port %d is up to date\n", port.Port)
+			continue
+		}
+		fmt.Printf("opening outbound port %d to %v\n", port.Port, toAdd)
+		if o.DryRun {
+			continue
+		}
+		err = cli.CreateOutboundPort(o.StackName, acs.OutboundPort{Port: port.Port, Subnets: toAdd}, o.Reason)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acsmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboundPorts(t *testing.T) {
+	assert := assert.New(t)
+	port := acs.OutboundPort{Port: 8443, Subnets: []string{"10.0.0.0/24"}}
+	cli := &acsmock.Client{}
+	cli.On("CreateOutboundPort", "test-stack", port, "webhook").Return(nil)
+	cli.On("ListOutboundPorts", "test-stack").Return([]acs.OutboundPort{port}, nil)
+	cli.On("DescribeOutboundPort", "test-stack", 8443).Return(&port, nil)
+	cli.On("DeleteOutboundPort", "test-stack", 8443, port.Subnets).Return(nil)
+
+	create := &outboundPortsCreate{StackName: "test-stack", Port: 8443, Subnets: port.Subnets, Reason: "webhook"}
+	assert.Nil(create.Run(&context{ACS: cli}))
+	out := captureStdout(t, func() {
+		assert.Nil((&outboundPortsList{StackName: "test-stack"}).Run(&context{ACS: cli}))
+	})
+	assert.JSONEq(`[{"port": 8443, "subnets": ["10.0.0.0/24"]}]`, out)
+	out = captureStdout(t, func() {
+		assert.Nil((&outboundPortsGet{StackName: "test-stack", Port: 8443}).Run(&context{ACS: cli}))
+	})
+	assert.JSONEq(`{"port": 8443, "subnets": ["10.0.0.0/24"]}`, out)
+	remove := &outboundPortsDelete{StackName: "test-stack", Port: 8443, Subnets: port.Subnets}
+	assert.Nil(remove.Run(&context{ACS: cli}))
+	cli.AssertExpectations(t)
+}
+
+func TestOutboundPortsApply(t *testing.T) {
+	assert := assert.New(t)
+	file := filepath.Join(t.TempDir(), "ports.json")
+	assert.Nil(ioutil.WriteFile(file, []byte(`[{"port": 8443, "subnets": ["10.0.0.0/24", "10.0.1.0/24"]},
+		{"port": 9997, "subnets": ["10.0.2.0/24"]}]`), 0644))
+
+	cli := &acsmock.Client{}
+	cli.On("ListOutboundPorts", "test-stack").
+		Return([]acs.OutboundPort{{Port: 8443, Subnets: []string{"10.0.0.0/24"}}, {Port: 9997, Subnets: []string{"10.0.2.0/24"}}}, nil)
+	cli.On("CreateOutboundPort", "test-stack", acs.OutboundPort{Port: 8443, Subnets: []string{"10.0.1.0/24"}}, "forwarding").
+		Return(errors.New("400 Bad Request"))
+
+	o := &outboundPortsApply{StackName: "test-stack", PortsFile: file, Reason: "forwarding", DryRun: true}
+	out := captureStdout(t, func() { assert.Nil(o.Run(&context{ACS: cli})) })
+	assert.Equal("opening outbound port 8443 to [10.0.1.0/24]\noutbound port 9997 is up to date\n", out)
+	cli.AssertNotCalled(t, "CreateOutboundPort", "test-stack",
+		acs.OutboundPort{Port: 8443, Subnets: []string{"10.0.1.0/24"}}, "forwarding")
+
+	// the ports after a failed one are not applied
+	o.DryRun = false
+	out = captureStdout(t, func() { assert.EqualError(o.Run(&context{ACS: cli}), "400 Bad Request") })
+	assert.Equal("opening outbound port 8443 to [10.0.1.0/24]\n", out)
+	cli.AssertExpectations(t)
+}
@@ -0,0 +1,91 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/splunk/acs-privateapps-demo/src/upload"
+	"golang.org/x/term"
+)
+
+const progressBarWidth = 30
+
+// openPackage streams the package at path, drawing its upload progress when stderr is a terminal
+func openPackage(path string) (*upload.Package, error) {
+	pkg, err := upload.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if isTerminal(os.Stderr) {
+		pkg.OnProgress(newProgressBar(os.Stderr, "uploading "+pkg.Name()).update)
+	}
+	return pkg, nil
+}
+
+// isTerminal reports whether f is a terminal, character devices such as /dev/null are not
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// progressBar redraws a single line every time the upload moves forward by a percent, or by a megabyte
+// when the size of the package is not known
+type progressBar struct {
+	w     io.Writer
+	label string
+	drawn int64
+}
+
+func newProgressBar(w io.Writer, label string) *progressBar {
+	return &progressBar{w: w, label: label, drawn: -1}
+}
+
+func (p *progressBar) update(sent, total int64) {
+	if total <= 0 {
+		if mb := sent >> 20; mb != p.drawn {
+			p.drawn = mb
+			fmt.Fprintf(p.w, "\r%s %s", p.label, byteCount(sent))
+		}
+		return
+	}
+	percent := sent * 100 / total
+	if percent == p.drawn {
+		return
+	}
+	p.drawn = percent
+	done := int(percent * progressBarWidth / 100)
+	fmt.Fprintf(p.w, "\r%s [%s%s] %3d%% %s/%s", p.label, strings.Repeat("=", done),
+		strings.Repeat(" ", progressBarWidth-done), percent, byteCount(sent), byteCount(total))
+	if sent >= total {
+		fmt.Fprintln(p.w)
+	}
+}
+
+// byteCount formats a number of bytes for humans, i.e. 1.5 MB
+func byteCount(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "KMGTPE"[exp])
+}
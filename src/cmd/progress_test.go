@@ -0,0 +1,38 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressBar(t *testing.T) {
+	var out bytes.Buffer
+	p := newProgressBar(&out, "uploading")
+	p.update(512, 2048)
+	p.update(513, 2048)
+	p.update(2048, 2048)
+	assert.Equal(t, "\ruploading [=======                       ]  25% 512 B/2.0 KB"+
+		"\ruploading [==============================] 100% 2.0 KB/2.0 KB\n", out.String())
+}
+
+func TestByteCount(t *testing.T) {
+	assert.Equal(t, "12 B", byteCount(12))
+	assert.Equal(t, "1.5 KB", byteCount(1536))
+	assert.Equal(t, "3.0 MB", byteCount(3<<20))
+}
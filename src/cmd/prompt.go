@@ -0,0 +1,143 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/terminal"
+)
+
+// prompt asks for a credential which was given neither on the command line, nor in the environment, nor in
+// a file
+type prompt struct {
+	value *string
+	// name is shown in the prompt and in the non-interactive errors, e.g. "stack token"
+	name string
+	// file is read into value when set, - reads stdin
+	file *string
+	// stdin reads value from stdin when set
+	stdin *bool
+	// flags and env tell how to pass the credential without being asked
+	flags  []string
+	env    string
+	secret bool
+	// optional values are left empty rather than asked for
+	optional bool
+}
+
+// load reads the credential from the file or stdin of the prompt, or resolves the file:// or env://
+// reference of its value
+func (p prompt) load() error {
+	fromStdin := p.stdin != nil && *p.stdin
+	fromFile := p.file != nil && *p.file != ""
+	var err error
+	switch {
+	case fromStdin && fromFile:
+		return fmt.Errorf("the %s can be read either from stdin or from a file, not both", p.name)
+	case fromStdin:
+		*p.value, err = readSecret("-")
+		*p.stdin = false
+	case fromFile:
+		*p.value, err = readSecret(*p.file)
+		*p.file = ""
+	default:
+		*p.value, err = resolveSecret(*p.value)
+	}
+	if err != nil {
+		return fmt.Errorf("error while reading the %s: %s", p.name, err)
+	}
+	return nil
+}
+
+// readsStdin reports whether the prompt loads its credential from stdin, through its stdin flag, its file
+// flag or a file://- reference, in the order load looks at them
+func (p prompt) readsStdin() bool {
+	switch {
+	case p.stdin != nil && *p.stdin:
+		return true
+	case p.file != nil && *p.file != "":
+		return *p.file == "-"
+	}
+	return p.value != nil && *p.value == "file://-"
+}
+
+// askOne is survey.AskOne, replaced by the tests
+var askOne = survey.AskOne
+
+// ask loads the credentials of the prompts, then prompts, in order, for the ones which are still missing. In
+// non-interactive mode it fails right away listing every missing credential instead.
+func (c *context) ask(prompts ...prompt) error {
+	readers := 0
+	for _, p := range prompts {
+		if p.readsStdin() {
+			readers++
+		}
+	}
+	if readers > 1 {
+		return fmt.Errorf("only one credential can be read from stdin")
+	}
+	var missing []prompt
+	for _, p := range prompts {
+		if err := p.load(); err != nil {
+			return err
+		}
+		if *p.value == "" && !p.optional {
+			missing = append(missing, p)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	if c.NonInteractive {
+		var names []string
+		for _, p := range missing {
+			names = append(names, fmt.Sprintf("%s (%s or %s)", p.name, strings.Join(p.flags, ", "), p.env))
+		}
+		return fmt.Errorf("missing credentials in non-interactive mode: %s", strings.Join(names, ", "))
+	}
+	for _, p := range missing {
+		var q survey.Prompt = &survey.Input{Message: p.name + ":"}
+		if p.secret {
+			q = &survey.Password{Message: p.name + ":"}
+		}
+		err := askOne(q, p.value, survey.WithValidator(survey.Required))
+		if p.secret {
+			fmt.Println("")
+		}
+		if err == terminal.InterruptErr {
+			return fmt.Errorf("interrupted while prompting for the %s", p.name)
+		}
+		if err != nil {
+			return fmt.Errorf("error while prompting for the %s: %s", p.name, err)
+		}
+	}
+	return nil
+}
+
+// confirm asks a yes/no question, the callers make sure not to ask in non-interactive mode
+func (c *context) confirm(message string) (bool, error) {
+	confirmed := false
+	err := askOne(&survey.Confirm{Message: message}, &confirmed)
+	if err == terminal.InterruptErr {
+		return false, fmt.Errorf("interrupted while asking for confirmation")
+	}
+	if err != nil {
+		return false, fmt.Errorf("error while asking for confirmation: %s", err)
+	}
+	return confirmed, nil
+}
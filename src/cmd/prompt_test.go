@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acsmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubAsk makes the prompts answer with the answers in order, or fail with err once they ran out
+func stubAsk(t *testing.T, err error, answers ...interface{}) *[]string {
+	var asked []string
+	askOne = func(p survey.Prompt, response interface{}, opts ...survey.AskOpt) error {
+		switch q := p.(type) {
+		case *survey.Input:
+			asked = append(asked, q.Message)
+		case *survey.Password:
+			asked = append(asked, q.Message)
+		case *survey.Confirm:
+			asked = append(asked, q.Message)
+		}
+		if len(answers) == 0 {
+			return err
+		}
+		switch r := response.(type) {
+		case *string:
+			*r = answers[0].(string)
+		case *bool:
+			*r = answers[0].(bool)
+		}
+		answers = answers[1:]
+		return nil
+	}
+	t.Cleanup(func() { askOne = survey.AskOne })
+	return &asked
+}
+
+func TestAskNonInteractive(t *testing.T) {
+	asked := stubAsk(t, nil)
+	i := &install{StackName: "test-stack", PackageFilePath: "missing.tar.gz",
+		splunkComFlags: splunkComFlags{SplunkComUsername: "user"}}
+	err := i.Run(&context{NonInteractive: true})
+	assert.EqualError(t, err, "missing credentials in non-interactive mode: "+
+		"stack token (--stack-token, --stack-token-file or STACK_TOKEN), "+
+		"splunkbase password (--splunk-com-password, --splunk-com-password-file, --password-stdin or SPLUNK_COM_PASSWORD)")
+	assert.Empty(t, *asked)
+}
+
+func TestAskInteractive(t *testing.T) {
+	assert := assert.New(t)
+	asked := stubAsk(t, nil, "user", "pass")
+	l := &login{}
+	auth := testAuthenticator("token")
+	assert.Nil(l.Run(&context{Authenticator: auth}))
+	assert.Equal([]string{"splunkbase username:", "splunkbase password:"}, *asked)
+	auth.AssertExpectations(t)
+}
+
+func TestAskErrors(t *testing.T) {
+	stubAsk(t, terminal.InterruptErr)
+	assert.EqualError(t, (&login{}).Run(&context{}), "interrupted while prompting for the splunkbase username")
+	stubAsk(t, errors.New("EOF"), "user")
+	assert.EqualError(t, (&login{}).Run(&context{}), "error while prompting for the splunkbase password: EOF")
+}
+
+func TestUninstallConfirmation(t *testing.T) {
+	assert := assert.New(t)
+	cli := &acsmock.Client{}
+	u := &uninstall{StackName: "test-stack", AppName: "testapp"}
+	assert.EqualError(u.Run(&context{ACS: cli, NonInteractive: true}),
+		"uninstall needs a confirmation, pass --yes in non-interactive mode")
+	cli.AssertExpectations(t)
+
+	cli.On("DescribeApp", "test-stack", "testapp").Return(&acs.App{Status: "installed"}, nil)
+	cli.On("ListMaintenanceWindows", "test-stack").Return(nil, nil)
+	asked := stubAsk(t, nil, false)
+	assert.EqualError(u.Run(&context{ACS: cli}), "uninstall aborted")
+	assert.Equal([]string{"uninstall app 'testapp' from stack 'test-stack'?"}, *asked)
+	cli.AssertNotCalled(t, "UninstallApp", "test-stack", "testapp")
+}
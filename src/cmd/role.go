@@ -0,0 +1,147 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+)
+
+type role struct {
+	Create roleCreate `kong:"cmd,help='create a role on the splunk stack'"`
+	List   roleList   `kong:"cmd,help='list the roles on the splunk stack'"`
+	Get    roleGet    `kong:"cmd,help='get a role on the splunk stack'"`
+	Update roleUpdate `kong:"cmd,help='update a role on the splunk stack'"`
+	Delete roleDelete `kong:"cmd,help='delete a role from the splunk stack'"`
+}
+
+// roleSettingsFlags are the flags for the settings which can be changed after a role was created
+type roleSettingsFlags struct {
+	Capabilities       []string    `kong:"help='comma separated list of the capabilities of the role'"`
+	ImportedRoles      []string    `kong:"help='comma separated list of the roles the role inherits from'"`
+	SrchIndexesAllowed []string    `kong:"help='comma separated list of the indexes the role can search'"`
+	SrchIndexesDefault []string    `kong:"help='comma separated list of the indexes searched when a search does not specify any'"`
+	SrchJobsQuota      optionalInt `kong:"placeholder='INT',help='the maximum number of concurrent searches of a user'"`
+	RtSrchJobsQuota    optionalInt `kong:"placeholder='INT',help='the maximum number of concurrent real-time searches of a user'"`
+	SrchDiskQuota      optionalInt `kong:"placeholder='INT',help='the maximum disk space in MB the search jobs of a user can use'"`
+	SrchTimeWin        optionalInt `kong:"placeholder='INT',help='the maximum time range in seconds of a search'"`
+	DefaultApp         string      `kong:"help='the app users with the role land in'"`
+}
+
+func (f *roleSettingsFlags) settings() acs.RoleSettings {
+	return acs.RoleSettings{
+		Capabilities:       f.Capabilities,
+		ImportedRoles:      f.ImportedRoles,
+		SrchIndexesAllowed: f.SrchIndexesAllowed,
+		SrchIndexesDefault: f.SrchIndexesDefault,
+		SrchJobsQuota:      f.SrchJobsQuota.value,
+		RtSrchJobsQuota:    f.RtSrchJobsQuota.value,
+		SrchDiskQuota:      f.SrchDiskQuota.value,
+		SrchTimeWin:        f.SrchTimeWin.value,
+		DefaultApp:         f.DefaultApp,
+	}
+}
+
+type roleCreate struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	RoleName  string `kong:"arg,help='the role'"`
+	roleSettingsFlags
+	stackFlags
+}
+
+func (r *roleCreate) Run(c *context) error {
+	cli, err := r.acsClient(c, r.StackName)
+	if err != nil {
+		return err
+	}
+	err = cli.CreateRole(r.StackName, acs.Role{Name: r.RoleName, RoleSettings: r.settings()})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("created role '%s'\n", r.RoleName)
+	return nil
+}
+
+type roleList struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	stackFlags
+}
+
+func (r *roleList) Run(c *context) error {
+	cli, err := r.acsClient(c, r.StackName)
+	if err != nil {
+		return err
+	}
+	roles, err := cli.ListRoles(r.StackName)
+	if err != nil {
+		return err
+	}
+	printJSON(roles)
+	return nil
+}
+
+type roleGet struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	RoleName  string `kong:"arg,help='the role'"`
+	stackFlags
+}
+
+func (r *roleGet) Run(c *context) error {
+	cli, err := r.acsClient(c, r.StackName)
+	if err != nil {
+		return err
+	}
+	ro, err := cli.DescribeRole(r.StackName, r.RoleName)
+	if err != nil {
+		return err
+	}
+	printJSON(ro)
+	return nil
+}
+
+type roleUpdate struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	RoleName  string `kong:"arg,help='the role'"`
+	roleSettingsFlags
+	stackFlags
+}
+
+func (r *roleUpdate) Run(c *context) error {
+	cli, err := r.acsClient(c, r.StackName)
+	if err != nil {
+		return err
+	}
+	err = cli.UpdateRole(r.StackName, r.RoleName, r.settings())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("updated role '%s'\n", r.RoleName)
+	return nil
+}
+
+type roleDelete struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	RoleName  string `kong:"arg,help='the role'"`
+	stackFlags
+}
+
+func (r *roleDelete) Run(c *context) error {
+	cli, err := r.acsClient(c, r.StackName)
+	if err != nil {
+		return err
+	}
+	return cli.DeleteRole(r.StackName, r.RoleName)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acsmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleCreate(t *testing.T) {
+	role := acs.Role{Name: "ci", RoleSettings: acs.RoleSettings{ImportedRoles: []string{"user"}, DefaultApp: "search"}}
+	cli := &acsmock.Client{}
+	cli.On("CreateRole", "test-stack", role).Return(nil)
+
+	r := &roleCreate{StackName: "test-stack", RoleName: "ci",
+		roleSettingsFlags: roleSettingsFlags{ImportedRoles: []string{"user"}, DefaultApp: "search"}}
+	out := captureStdout(t, func() { assert.Nil(t, r.Run(&context{ACS: cli})) })
+	assert.Equal(t, "created role 'ci'\n", out)
+	cli.AssertExpectations(t)
+}
+
+func TestRoleListGetUpdateDelete(t *testing.T) {
+	assert := assert.New(t)
+	quota := 10
+	role := acs.Role{Name: "ci", RoleSettings: acs.RoleSettings{ImportedRoles: []string{"user"}}}
+	cli := &acsmock.Client{}
+	cli.On("ListRoles", "test-stack").Return([]acs.Role{role}, nil)
+	cli.On("DescribeRole", "test-stack", "ci").Return(&role, nil)
+	cli.On("UpdateRole", "test-stack", "ci", acs.RoleSettings{SrchJobsQuota: &quota}).Return(nil)
+	cli.On("DeleteRole", "test-stack", "ci").Return(nil)
+
+	out := captureStdout(t, func() { assert.Nil((&roleList{StackName: "test-stack"}).Run(&context{ACS: cli})) })
+	assert.JSONEq(`[{"name": "ci", "importedRoles": ["user"]}]`, out)
+	out = captureStdout(t, func() {
+		assert.Nil((&roleGet{StackName: "test-stack", RoleName: "ci"}).Run(&context{ACS: cli}))
+	})
+	assert.JSONEq(`{"name": "ci", "importedRoles": ["user"]}`, out)
+	update := &roleUpdate{StackName: "test-stack", RoleName: "ci",
+		roleSettingsFlags: roleSettingsFlags{SrchJobsQuota: optionalInt{value: &quota}}}
+	out = captureStdout(t, func() { assert.Nil(update.Run(&context{ACS: cli})) })
+	assert.Equal("updated role 'ci'\n", out)
+	assert.Nil((&roleDelete{StackName: "test-stack", RoleName: "ci"}).Run(&context{ACS: cli}))
+	cli.AssertExpectations(t)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs/acstest"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubStdin makes the secrets passed as - read input
+func stubStdin(t *testing.T, input string) {
+	stdin = strings.NewReader(input)
+	t.Cleanup(func() { stdin = os.Stdin })
+}
+
+func TestResolveSecret(t *testing.T) {
+	assert := assert.New(t)
+	file := filepath.Join(t.TempDir(), "secret")
+	assert.Nil(ioutil.WriteFile(file, []byte("from-file\n"), 0600))
+	os.Setenv("CLOUDCTL_TEST_SECRET", "from-env")
+	defer os.Unsetenv("CLOUDCTL_TEST_SECRET")
+
+	for value, expected := range map[string]string{
+		"plain":                      "plain",
+		"file://" + file:             "from-file",
+		"env://CLOUDCTL_TEST_SECRET": "from-env",
+		"":                           "",
+	} {
+		secret, err := resolveSecret(value)
+		assert.Nil(err)
+		assert.Equal(expected, secret)
+	}
+	_, err := resolveSecret("env://CLOUDCTL_TEST_UNSET")
+	assert.EqualError(err, "environment variable CLOUDCTL_TEST_UNSET is not set")
+	_, err = resolveSecret("file://" + filepath.Join(t.TempDir(), "missing"))
+	assert.Error(err)
+}
+
+func TestSecretFiles(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	assert.Nil(ioutil.WriteFile(tokenFile, []byte(testStackToken()+"\n"), 0600))
+	stubStdin(t, "pass\n")
+
+	s := &stackFlags{StackTokenFile: tokenFile}
+	sc := &splunkComFlags{SplunkComUsername: "user", PasswordStdin: true}
+	c := &context{NonInteractive: true}
+	assert.Nil(c.ask(append(s.tokenPrompts(c), sc.credentialPrompts(c)...)...))
+	assert.Equal(testStackToken(), s.StackToken)
+	assert.Equal("pass", sc.SplunkComPassword)
+
+	// the secrets are read once, asking again keeps them
+	assert.Nil(c.ask(append(s.tokenPrompts(c), sc.credentialPrompts(c)...)...))
+	assert.Equal("pass", sc.SplunkComPassword)
+}
+
+func TestSecretStdinConflicts(t *testing.T) {
+	assert := assert.New(t)
+	stubStdin(t, "secret")
+	c := &context{NonInteractive: true}
+
+	s := &stackFlags{StackTokenFile: "-"}
+	sc := &splunkComFlags{SplunkComUsername: "user", PasswordStdin: true}
+	assert.EqualError(c.ask(append(s.tokenPrompts(c), sc.credentialPrompts(c)...)...),
+		"only one credential can be read from stdin")
+
+	// a file://- reference reads stdin too
+	s = &stackFlags{StackToken: "file://-"}
+	assert.EqualError(c.ask(append(s.tokenPrompts(c), sc.credentialPrompts(c)...)...),
+		"only one credential can be read from stdin")
+	s = &stackFlags{StackToken: "file://-", StackTokenFile: "token"}
+	sc = &splunkComFlags{SplunkComUsername: "file://-", SplunkComPassword: "pass"}
+	assert.EqualError(c.ask(append(s.tokenPrompts(c), sc.credentialPrompts(c)...)...),
+		"error while reading the stack token: open token: no such file or directory")
+
+	sc = &splunkComFlags{SplunkComUsername: "user", PasswordStdin: true, SplunkComPasswordFile: "password"}
+	assert.EqualError(c.ask(sc.credentialPrompts(c)...),
+		"the splunkbase password can be read either from stdin or from a file, not both")
+}
+
+func TestGetWithStackTokenReference(t *testing.T) {
+	fake, srv := acstest.NewServer()
+	defer srv.Close()
+	fake.SetToken("opaque-token")
+	os.Setenv("CLOUDCTL_TEST_TOKEN", "opaque-token")
+	defer os.Unsetenv("CLOUDCTL_TEST_TOKEN")
+
+	g := &get{StackName: "test-stack", stackFlags: stackFlags{StackToken: "env://CLOUDCTL_TEST_TOKEN", AcsURL: srv.URL}}
+	assert.Nil(t, g.Run(&context{NonInteractive: true}))
+	assert.Equal(t, "opaque-token", g.StackToken)
+}
+
+func TestLoginPasswordStdin(t *testing.T) {
+	stubStdin(t, "pass\n")
+	auth := testAuthenticator("token")
+
+	l := &login{splunkComFlags: splunkComFlags{SplunkComUsername: "user", PasswordStdin: true}}
+	assert.Nil(t, l.Run(&context{Authenticator: auth, NonInteractive: true}))
+	auth.AssertExpectations(t)
+}
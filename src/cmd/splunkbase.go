@@ -0,0 +1,154 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/splunk/acs-privateapps-demo/src/pipeline"
+)
+
+type splunkbase struct {
+	Install   splunkbaseInstall   `kong:"cmd,help='install a splunkbase app on the splunk stack'"`
+	List      splunkbaseList      `kong:"cmd,help='list the splunkbase apps installed on the splunk stack'"`
+	Get       splunkbaseGet       `kong:"cmd,help='get a splunkbase app installed on the splunk stack'"`
+	Update    splunkbaseUpdate    `kong:"cmd,help='update a splunkbase app installed on the splunk stack'"`
+	Uninstall splunkbaseUninstall `kong:"cmd,help='uninstall a splunkbase app from the splunk stack'"`
+}
+
+type splunkbaseInstall struct {
+	StackName    string `kong:"arg,help='the splunk cloud stack'"`
+	SplunkbaseID string `kong:"arg,help='the splunkbase id of the app'"`
+	Version      string `kong:"help='the app version to install, defaults to the latest compatible version'"`
+	LicenseURL   string `kong:"required,help='the url of the app license, passing it acknowledges the license'"`
+	splunkComFlags
+	stackFlags
+}
+
+func (s *splunkbaseInstall) Run(c *context) error {
+	if err := c.ask(append(s.tokenPrompts(c), s.credentialPrompts(c)...)...); err != nil {
+		return err
+	}
+	cli, err := s.acsClient(c, s.StackName)
+	if err != nil {
+		return err
+	}
+	token, err := s.authenticate(c)
+	if err != nil {
+		return err
+	}
+	err = cli.InstallSplunkbaseApp(s.StackName, token, s.SplunkbaseID, s.Version, s.LicenseURL)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("installed splunkbase app (splunkbaseID='%s')\n", s.SplunkbaseID)
+	return nil
+}
+
+type splunkbaseList struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	stackFlags
+}
+
+func (s *splunkbaseList) Run(c *context) error {
+	cli, err := s.acsClient(c, s.StackName)
+	if err != nil {
+		return err
+	}
+	apps, err := cli.ListSplunkbaseApps(s.StackName)
+	if err != nil {
+		return err
+	}
+	printJSON(apps)
+	return nil
+}
+
+type splunkbaseGet struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	AppName   string `kong:"arg,help='the app'"`
+	stackFlags
+}
+
+func (s *splunkbaseGet) Run(c *context) error {
+	cli, err := s.acsClient(c, s.StackName)
+	if err != nil {
+		return err
+	}
+	app, err := cli.DescribeSplunkbaseApp(s.StackName, s.AppName)
+	if err != nil {
+		return err
+	}
+	printJSON(app)
+	return nil
+}
+
+type splunkbaseUpdate struct {
+	StackName  string `kong:"arg,help='the splunk cloud stack'"`
+	AppName    string `kong:"arg,help='the app'"`
+	Version    string `kong:"help='the app version to update to, defaults to the latest compatible version'"`
+	LicenseURL string `kong:"required,help='the url of the app license, passing it acknowledges the license'"`
+	splunkComFlags
+	stackFlags
+}
+
+func (s *splunkbaseUpdate) Run(c *context) error {
+	if err := c.ask(append(s.tokenPrompts(c), s.credentialPrompts(c)...)...); err != nil {
+		return err
+	}
+	cli, err := s.acsClient(c, s.StackName)
+	if err != nil {
+		return err
+	}
+	token, err := s.authenticate(c)
+	if err != nil {
+		return err
+	}
+	err = cli.UpdateSplunkbaseApp(s.StackName, token, s.AppName, s.Version, s.LicenseURL)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("updated splunkbase app '%s'\n", s.AppName)
+	return nil
+}
+
+type splunkbaseUninstall struct {
+	StackName     string   `kong:"arg,help='the splunk cloud stack'"`
+	AppName       string   `kong:"arg,help='the app'"`
+	Yes           bool     `kong:"short='y',help='uninstall without asking for confirmation'"`
+	ProtectedApps []string `kong:"env='PROTECTED_APPS',help='comma separated list of apps that must never be uninstalled'"`
+	stackFlags
+}
+
+func (s *splunkbaseUninstall) Run(c *context) error {
+	// protected apps are refused before prompting for the stack token
+	if err := checkUninstall(c, s.AppName, s.Yes, s.ProtectedApps); err != nil {
+		return err
+	}
+
+	cli, err := s.acsClient(c, s.StackName)
+	if err != nil {
+		return err
+	}
+	if !s.Yes {
+		confirmed, err := c.confirm(fmt.Sprintf("uninstall splunkbase app '%s' from stack '%s'?", s.AppName, s.StackName))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return pipeline.ErrUninstallAborted
+		}
+	}
+	return cli.UninstallSplunkbaseApp(s.StackName, s.AppName)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acsmock"
+	"github.com/splunk/acs-privateapps-demo/src/appinspect"
+	"github.com/splunk/acs-privateapps-demo/src/appinspect/appinspectmock"
+	"github.com/splunk/acs-privateapps-demo/src/pipeline"
+	"github.com/stretchr/testify/assert"
+)
+
+// testAuthenticator returns a mock authenticator logging user/pass in with token
+func testAuthenticator(token string) *appinspectmock.Authenticator {
+	res := &appinspect.AuthenticateResult{}
+	res.Data.Token = token
+	auth := &appinspectmock.Authenticator{}
+	auth.On("Authenticate", "user", "pass").Return(res, nil)
+	return auth
+}
+
+func TestSplunkbaseInstallUpdate(t *testing.T) {
+	assert := assert.New(t)
+	cli := &acsmock.Client{}
+	auth := testAuthenticator("token")
+	cli.On("InstallSplunkbaseApp", "test-stack", "token", "1621", "", "https://example.com/license").Return(nil)
+	cli.On("UpdateSplunkbaseApp", "test-stack", "token", "Splunk_SA_CIM", "5.0.0", "https://example.com/license").
+		Return(nil)
+	c := &context{ACS: cli, Authenticator: auth}
+	credentials := splunkComFlags{SplunkComUsername: "user", SplunkComPassword: "pass"}
+
+	i := &splunkbaseInstall{StackName: "test-stack", SplunkbaseID: "1621", LicenseURL: "https://example.com/license",
+		splunkComFlags: credentials}
+	out := captureStdout(t, func() { assert.Nil(i.Run(c)) })
+	assert.Equal("installed splunkbase app (splunkbaseID='1621')\n", out)
+	u := &splunkbaseUpdate{StackName: "test-stack", AppName: "Splunk_SA_CIM", Version: "5.0.0",
+		LicenseURL: "https://example.com/license", splunkComFlags: credentials}
+	out = captureStdout(t, func() { assert.Nil(u.Run(c)) })
+	assert.Equal("updated splunkbase app 'Splunk_SA_CIM'\n", out)
+	cli.AssertExpectations(t)
+	auth.AssertExpectations(t)
+}
+
+func TestSplunkbaseListGetUninstall(t *testing.T) {
+	assert := assert.New(t)
+	id, name := "1621", "Splunk_SA_CIM"
+	app := acs.App{Name: &name, Status: "installed", SplunkbaseID: &id}
+	cli := &acsmock.Client{}
+	cli.On("ListSplunkbaseApps", "test-stack").Return([]acs.App{app}, nil)
+	cli.On("DescribeSplunkbaseApp", "test-stack", "Splunk_SA_CIM").Return(&app, nil)
+	cli.On("UninstallSplunkbaseApp", "test-stack", "Splunk_SA_CIM").Return(nil)
+
+	out := captureStdout(t, func() { assert.Nil((&splunkbaseList{StackName: "test-stack"}).Run(&context{ACS: cli})) })
+	assert.JSONEq(`[{"name": "Splunk_SA_CIM", "status": "installed", "splunkbaseID": "1621"}]`, out)
+	out = captureStdout(t, func() {
+		assert.Nil((&splunkbaseGet{StackName: "test-stack", AppName: "Splunk_SA_CIM"}).Run(&context{ACS: cli}))
+	})
+	assert.JSONEq(`{"name": "Splunk_SA_CIM", "status": "installed", "splunkbaseID": "1621"}`, out)
+	uninstall := &splunkbaseUninstall{StackName: "test-stack", AppName: "Splunk_SA_CIM", Yes: true}
+	assert.Nil(uninstall.Run(&context{ACS: cli}))
+	cli.AssertExpectations(t)
+}
+
+func TestSplunkbaseUninstallConfirmation(t *testing.T) {
+	assert := assert.New(t)
+	cli := &acsmock.Client{}
+	cli.On("UninstallSplunkbaseApp", "test-stack", "Splunk_SA_CIM").Return(nil).Once()
+
+	// declined uninstalls are aborted
+	asked := stubAsk(t, nil, false)
+	uninstall := &splunkbaseUninstall{StackName: "test-stack", AppName: "Splunk_SA_CIM"}
+	assert.Equal(pipeline.ErrUninstallAborted, uninstall.Run(&context{ACS: cli}))
+	assert.Equal([]string{"uninstall splunkbase app 'Splunk_SA_CIM' from stack 'test-stack'?"}, *asked)
+	cli.AssertNotCalled(t, "UninstallSplunkbaseApp", "test-stack", "Splunk_SA_CIM")
+
+	asked = stubAsk(t, nil, true)
+	assert.Nil(uninstall.Run(&context{ACS: cli}))
+	assert.Len(*asked, 1)
+
+	// non-interactive uninstalls need --yes
+	assert.EqualError(uninstall.Run(&context{ACS: cli, NonInteractive: true}),
+		"uninstall needs a confirmation, pass --yes in non-interactive mode")
+	cli.AssertExpectations(t)
+}
+
+func TestSplunkbaseUninstallProtectedApp(t *testing.T) {
+	cli := &acsmock.Client{}
+	asked := stubAsk(t, nil, true)
+
+	uninstall := &splunkbaseUninstall{StackName: "test-stack", AppName: "Splunk_SA_CIM", Yes: true,
+		ProtectedApps: []string{"search", "Splunk_SA_CIM"}}
+	assert.EqualError(t, uninstall.Run(&context{ACS: cli}), "app 'Splunk_SA_CIM' is protected and cannot be uninstalled")
+	assert.Empty(t, *asked)
+	cli.AssertNotCalled(t, "UninstallSplunkbaseApp", "test-stack", "Splunk_SA_CIM")
+}
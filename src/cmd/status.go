@@ -0,0 +1,57 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+)
+
+type status struct {
+	StackName  string `kong:"arg,help='the splunk cloud stack'"`
+	MinVersion string `kong:"help='fail unless the stack runs this splunk version or newer'"`
+	stackFlags
+}
+
+func (s *status) Run(c *context) error {
+	cli, err := s.acsClient(c, s.StackName)
+	if err != nil {
+		return err
+	}
+	st, err := cli.StackStatus(s.StackName)
+	if err != nil {
+		return err
+	}
+	printJSON(st)
+
+	if !st.IsReady() {
+		return fmt.Errorf("stack is not ready (status='%s')", st.Infrastructure.StackStatus)
+	}
+	minVersion := s.MinVersion
+	if minVersion == "" && (s.Victoria || st.IsVictoria()) {
+		minVersion = acs.MinVictoriaVersion
+	}
+	if minVersion != "" {
+		ok, err := st.VersionAtLeast(minVersion)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("stack version '%s' is older than the required '%s'", st.Infrastructure.StackVersion, minVersion)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acsmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func testStackStatus(stackStatus, version string) *acs.StackStatus {
+	st := &acs.StackStatus{}
+	st.Infrastructure.StackType = "classic"
+	st.Infrastructure.StackStatus = stackStatus
+	st.Infrastructure.StackVersion = version
+	return st
+}
+
+func TestStatus(t *testing.T) {
+	assert := assert.New(t)
+	cli := &acsmock.Client{}
+	cli.On("StackStatus", "ready-stack").Return(testStackStatus(acs.StackStatusReady, "8.2.2112"), nil)
+	cli.On("StackStatus", "busy-stack").Return(testStackStatus("Provisioning", "8.2.2112"), nil)
+
+	s := &status{StackName: "ready-stack"}
+	out := captureStdout(t, func() { assert.Nil(s.Run(&context{ACS: cli})) })
+	assert.JSONEq(`{"infrastructure": {"stackType": "classic", "stackStatus": "Ready", "stackVersion": "8.2.2112"},
+		"messages": {"restartRequired": false}}`, out)
+	s.MinVersion = "9.0.0"
+	captureStdout(t, func() {
+		assert.EqualError(s.Run(&context{ACS: cli}), "stack version '8.2.2112' is older than the required '9.0.0'")
+	})
+
+	s = &status{StackName: "busy-stack"}
+	captureStdout(t, func() {
+		assert.EqualError(s.Run(&context{ACS: cli}), "stack is not ready (status='Provisioning')")
+	})
+	cli.AssertExpectations(t)
+}
@@ -0,0 +1,119 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+)
+
+type token struct {
+	Create  tokenCreate  `kong:"cmd,help='create an authentication token on the splunk stack'"`
+	List    tokenList    `kong:"cmd,help='list the authentication tokens on the splunk stack'"`
+	Delete  tokenDelete  `kong:"cmd,help='delete an authentication token from the splunk stack'"`
+	Inspect tokenInspect `kong:"cmd,help='decode the claims of a stack token locally, without verifying it'"`
+}
+
+type tokenCreate struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	User      string `kong:"required,help='the user the token authenticates as'"`
+	Audience  string `kong:"required,help='what the token is used for, e.g. the ci pipeline'"`
+	ExpiresOn string `kong:"help='when the token expires, either an RFC3339 time or relative such as +1h or +30d'"`
+	NotBefore string `kong:"help='when the token becomes valid, either an RFC3339 time or relative such as +1h'"`
+	TokenOnly bool   `kong:"help='only print the token value, e.g. to capture it into STACK_TOKEN'"`
+	stackFlags
+}
+
+func (t *tokenCreate) Run(c *context) error {
+	cli, err := t.acsClient(c, t.StackName)
+	if err != nil {
+		return err
+	}
+	created, err := cli.CreateAuthToken(t.StackName, acs.AuthTokenRequest{
+		User:      t.User,
+		Audience:  t.Audience,
+		ExpiresOn: t.ExpiresOn,
+		NotBefore: t.NotBefore,
+	})
+	if err != nil {
+		return err
+	}
+	if t.TokenOnly {
+		fmt.Println(created.Token)
+		return nil
+	}
+	printJSON(created)
+	return nil
+}
+
+type tokenList struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	stackFlags
+}
+
+func (t *tokenList) Run(c *context) error {
+	cli, err := t.acsClient(c, t.StackName)
+	if err != nil {
+		return err
+	}
+	tokens, err := cli.ListAuthTokens(t.StackName)
+	if err != nil {
+		return err
+	}
+	printJSON(tokens)
+	return nil
+}
+
+type tokenDelete struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	TokenID   string `kong:"arg,help='the id of the token'"`
+	stackFlags
+}
+
+func (t *tokenDelete) Run(c *context) error {
+	cli, err := t.acsClient(c, t.StackName)
+	if err != nil {
+		return err
+	}
+	return cli.DeleteAuthToken(t.StackName, t.TokenID)
+}
+
+type tokenInspect struct {
+	StackToken     string `kong:"env='STACK_TOKEN',help='the stack jwt token, file://<path> and env://<name> read it from a file or another environment variable'"`
+	StackTokenFile string `kong:"help='the file holding the stack token, - reads it from stdin'"`
+}
+
+func (t *tokenInspect) Run(c *context) error {
+	if err := c.ask(stackTokenPrompt(&t.StackToken, &t.StackTokenFile)); err != nil {
+		return err
+	}
+	claims, err := acs.ParseTokenClaims(t.StackToken)
+	if err != nil {
+		return err
+	}
+	printJSON(claims)
+	if err = claims.Check(time.Now()); err != nil {
+		return err
+	}
+	if claims.ExpiresAt == 0 {
+		fmt.Printf("token never expires\n")
+		return nil
+	}
+	fmt.Printf("token expires at %s (in %s)\n", claims.Expiry().Format(time.RFC3339),
+		time.Until(claims.Expiry()).Round(time.Second))
+	return nil
+}
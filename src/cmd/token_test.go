@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acsmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenCreate(t *testing.T) {
+	assert := assert.New(t)
+	request := acs.AuthTokenRequest{User: "sc_admin", Audience: "ci", ExpiresOn: "+1h"}
+	cli := &acsmock.Client{}
+	cli.On("CreateAuthToken", "test-stack", request).
+		Return(&acs.AuthToken{ID: "1", Token: "jwt", User: "sc_admin", Audience: "ci"}, nil)
+
+	tc := &tokenCreate{StackName: "test-stack", User: "sc_admin", Audience: "ci", ExpiresOn: "+1h"}
+	out := captureStdout(t, func() { assert.Nil(tc.Run(&context{ACS: cli})) })
+	assert.JSONEq(`{"id": "1", "token": "jwt", "user": "sc_admin", "audience": "ci"}`, out)
+
+	// only the token, so it can be captured into STACK_TOKEN
+	tc.TokenOnly = true
+	out = captureStdout(t, func() { assert.Nil(tc.Run(&context{ACS: cli})) })
+	assert.Equal("jwt\n", out)
+	cli.AssertExpectations(t)
+}
+
+func TestTokenList(t *testing.T) {
+	assert := assert.New(t)
+	cli := &acsmock.Client{}
+	cli.On("ListAuthTokens", "test-stack").Return([]acs.AuthToken{{ID: "1", User: "sc_admin", Audience: "ci"}}, nil)
+
+	out := captureStdout(t, func() { assert.Nil((&tokenList{StackName: "test-stack"}).Run(&context{ACS: cli})) })
+	assert.JSONEq(`[{"id": "1", "user": "sc_admin", "audience": "ci"}]`, out)
+	cli.AssertExpectations(t)
+}
+
+func TestTokenDelete(t *testing.T) {
+	cli := &acsmock.Client{}
+	cli.On("DeleteAuthToken", "test-stack", "1").Return(nil)
+
+	assert.Nil(t, (&tokenDelete{StackName: "test-stack", TokenID: "1"}).Run(&context{ACS: cli}))
+	cli.AssertExpectations(t)
+}
+func TestTokenInspect(t *testing.T) {
+	assert := assert.New(t)
+	c := &context{NonInteractive: true}
+	assert.Nil((&tokenInspect{StackToken: testStackToken()}).Run(c))
+	err := (&tokenInspect{}).Run(c)
+	assert.Error(err)
+	assert.Contains(err.Error(), "stack token (--stack-token, --stack-token-file or STACK_TOKEN)")
+	err = (&tokenInspect{StackToken: "eyJhbGciOiJub25lIn0.eyJleHAiOjF9."}).Run(c)
+	assert.Error(err)
+	assert.Contains(err.Error(), "expired")
+}
+
+func TestTokenInspectStdin(t *testing.T) {
+	stubStdin(t, testStackToken()+"\n")
+	assert.Nil(t, (&tokenInspect{StackTokenFile: "-"}).Run(&context{NonInteractive: true}))
+}
+
+func TestTokenInspectNotYetValid(t *testing.T) {
+	// never expires, but only becomes valid in an hour
+	claims, _ := json.Marshal(map[string]interface{}{"sub": "sc_admin", "nbf": time.Now().Add(time.Hour).Unix()})
+	token := "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString(claims) + "."
+	err := (&tokenInspect{StackToken: token}).Run(&context{NonInteractive: true})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not valid before")
+}
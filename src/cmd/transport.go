@@ -0,0 +1,63 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/splunk/acs-privateapps-demo/src/httpclient"
+)
+
+// transportFlags configure how cloudCtl reaches the services
+type transportFlags struct {
+	Proxy    string `kong:"help='the url of the proxy to reach the services through, HTTPS_PROXY is used by default'"`
+	CABundle string `kong:"name='ca-bundle',type='path',help='a pem file of certificate authorities to trust on top of the system ones'"`
+}
+
+// transport returns the transport with the proxy and certificate authorities of the flags, nil when the
+// default one will do
+func (t *transportFlags) transport() (http.RoundTripper, error) {
+	if t.Proxy == "" && t.CABundle == "" {
+		return nil, nil
+	}
+	config := httpclient.Config{}
+	if t.Proxy != "" {
+		proxy, err := url.Parse(t.Proxy)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy url '%s'", t.Proxy)
+		}
+		config.Proxy = proxy
+	}
+	if t.CABundle != "" {
+		pem, err := ioutil.ReadFile(t.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", t.CABundle)
+		}
+		config.TLSConfig = &tls.Config{RootCAs: pool}
+	}
+	return config.RoundTripper(), nil
+}
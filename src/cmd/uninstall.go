@@ -0,0 +1,74 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/pipeline"
+)
+
+type uninstall struct {
+	StackName     string   `kong:"arg,help='the splunk cloud stack'"`
+	AppName       string   `kong:"arg,help='the app'"`
+	Yes           bool     `kong:"short='y',help='uninstall without asking for confirmation'"`
+	ProtectedApps []string `kong:"env='PROTECTED_APPS',help='comma separated list of apps that must never be uninstalled'"`
+	maintenanceFlags
+	stackFlags
+}
+
+func (u *uninstall) Run(c *context) error {
+
+	// protected apps are refused before prompting for the stack token
+	if err := checkUninstall(c, u.AppName, u.Yes, u.ProtectedApps); err != nil {
+		return err
+	}
+
+	cli, err := u.acsClient(c, u.StackName)
+	if err != nil {
+		return err
+	}
+	opts := pipeline.UninstallOptions{
+		Client:        cli,
+		Stack:         u.StackName,
+		App:           u.AppName,
+		ProtectedApps: u.ProtectedApps,
+		Maintenance:   u.options(),
+		Logger:        c.Logger,
+		Out:           c.progress(),
+	}
+	if !u.Yes {
+		opts.Confirm = func(stack string, _ *acs.App) (bool, error) {
+			return c.confirm(fmt.Sprintf("uninstall app '%s' from stack '%s'?", u.AppName, stack))
+		}
+	}
+	_, err = pipeline.Uninstall(c.ctx(), opts)
+	return maintenanceError(err)
+}
+
+// checkUninstall makes sure an uninstall of the app can be confirmed, i.e. that --yes is given in
+// non-interactive mode, and that the app is not protected
+func checkUninstall(c *context, appName string, yes bool, protectedApps []string) error {
+	if !yes && c.NonInteractive {
+		return fmt.Errorf("uninstall needs a confirmation, pass --yes in non-interactive mode")
+	}
+	for _, app := range protectedApps {
+		if app == appName {
+			return fmt.Errorf("app '%s' is protected and cannot be uninstalled", appName)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/alecthomas/kong"
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acsmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUninstall(t *testing.T) {
+	cli := &acsmock.Client{}
+	cli.On("DescribeApp", "test-stack", "testapp").Return(&acs.App{Status: "installed"}, nil)
+	cli.On("ListMaintenanceWindows", "test-stack").Return([]acs.MaintenanceWindow{}, nil)
+	cli.On("UninstallApp", "test-stack", "testapp").Return(nil)
+
+	u := &uninstall{StackName: "test-stack", AppName: "testapp", Yes: true}
+	out := captureStdout(t, func() { assert.Nil(t, u.Run(&context{ACS: cli})) })
+	assert.Empty(t, out)
+	cli.AssertExpectations(t)
+}
+
+func TestUninstallRefused(t *testing.T) {
+	assert := assert.New(t)
+	id := "1621"
+	cli := &acsmock.Client{}
+	cli.On("DescribeApp", "test-stack", "Splunk_SA_CIM").Return(&acs.App{SplunkbaseID: &id}, nil)
+
+	u := &uninstall{StackName: "test-stack", AppName: "search", Yes: true, ProtectedApps: []string{"search"}}
+	assert.EqualError(u.Run(&context{ACS: cli}), "app 'search' is protected and cannot be uninstalled")
+	u = &uninstall{StackName: "test-stack", AppName: "Splunk_SA_CIM", Yes: true}
+	assert.EqualError(u.Run(&context{ACS: cli}), "app 'Splunk_SA_CIM' is not a private app (splunkbaseID='1621')")
+	cli.AssertNotCalled(t, "UninstallApp", "test-stack", "Splunk_SA_CIM")
+	cli.AssertExpectations(t)
+}
+
+// parseUninstall parses the arguments of the uninstall command as cloudCtl does, flags and environment
+func parseUninstall(t *testing.T, args ...string) *uninstall {
+	var cmd struct {
+		Uninstall uninstall `kong:"cmd"`
+	}
+	parser, err := kong.New(&cmd)
+	assert.Nil(t, err)
+	_, err = parser.Parse(append([]string{"uninstall"}, args...))
+	assert.Nil(t, err)
+	return &cmd.Uninstall
+}
+
+func TestUninstallProtectedAppsEnv(t *testing.T) {
+	os.Setenv("PROTECTED_APPS", "search,Splunk_SA_CIM")
+	defer os.Unsetenv("PROTECTED_APPS")
+	cli := &acsmock.Client{}
+
+	// protected apps are refused before the stack is looked at
+	for _, app := range []string{"search", "Splunk_SA_CIM"} {
+		u := parseUninstall(t, "test-stack", app, "--yes")
+		assert.Equal(t, []string{"search", "Splunk_SA_CIM"}, u.ProtectedApps)
+		assert.EqualError(t, u.Run(&context{ACS: cli, NonInteractive: true}),
+			fmt.Sprintf("app '%s' is protected and cannot be uninstalled", app))
+	}
+	cli.AssertNotCalled(t, "DescribeApp", "test-stack", "search")
+	cli.AssertExpectations(t)
+}
+
+func TestUninstallSplunkbaseApp(t *testing.T) {
+	id := "1621"
+	cli := &acsmock.Client{}
+	cli.On("DescribeApp", "test-stack", "Splunk_SA_CIM").Return(&acs.App{SplunkbaseID: &id}, nil)
+	asked := stubAsk(t, nil, true)
+
+	// splunkbase apps are refused before asking for a confirmation or checking the maintenance windows
+	u := parseUninstall(t, "test-stack", "Splunk_SA_CIM")
+	assert.EqualError(t, u.Run(&context{ACS: cli}), "app 'Splunk_SA_CIM' is not a private app (splunkbaseID='1621')")
+	assert.Empty(t, *asked)
+	cli.AssertNotCalled(t, "ListMaintenanceWindows", "test-stack")
+	cli.AssertNotCalled(t, "UninstallApp", "test-stack", "Splunk_SA_CIM")
+	cli.AssertExpectations(t)
+}
+
+func TestUninstallYes(t *testing.T) {
+	for _, flag := range []string{"--yes", "-y"} {
+		cli := &acsmock.Client{}
+		cli.On("DescribeApp", "test-stack", "testapp").Return(&acs.App{Status: "installed"}, nil)
+		cli.On("ListMaintenanceWindows", "test-stack").Return([]acs.MaintenanceWindow{}, nil)
+		cli.On("UninstallApp", "test-stack", "testapp").Return(nil)
+		asked := stubAsk(t, nil, false)
+
+		// the app is uninstalled without asking, in non-interactive mode too
+		u := parseUninstall(t, "test-stack", "testapp", flag)
+		assert.True(t, u.Yes)
+		assert.Nil(t, u.Run(&context{ACS: cli, NonInteractive: true}))
+		assert.Empty(t, *asked, flag)
+		cli.AssertExpectations(t)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+)
+
+type user struct {
+	Create userCreate `kong:"cmd,help='create a user on the splunk stack'"`
+	List   userList   `kong:"cmd,help='list the users on the splunk stack'"`
+	Get    userGet    `kong:"cmd,help='get a user on the splunk stack'"`
+	Update userUpdate `kong:"cmd,help='update a user on the splunk stack'"`
+	Delete userDelete `kong:"cmd,help='delete a user from the splunk stack'"`
+}
+
+// userSettingsFlags are the flags for the settings which can be changed after a user was created
+type userSettingsFlags struct {
+	Roles      []string `kong:"help='comma separated list of the roles of the user'"`
+	DefaultApp string   `kong:"help='the app the user lands in'"`
+	Email      string   `kong:"help='the email address of the user'"`
+	FullName   string   `kong:"help='the full name of the user'"`
+}
+
+func (f *userSettingsFlags) settings() acs.UserSettings {
+	return acs.UserSettings{
+		Roles:      f.Roles,
+		DefaultApp: f.DefaultApp,
+		Email:      f.Email,
+		FullName:   f.FullName,
+	}
+}
+
+type userCreate struct {
+	StackName    string `kong:"arg,help='the splunk cloud stack'"`
+	UserName     string `kong:"arg,help='the user'"`
+	UserPassword string `kong:"env='SPLUNK_USER_PASSWORD',help='the password of the new user'"`
+	userSettingsFlags
+	stackFlags
+}
+
+func (u *userCreate) Run(c *context) error {
+	password := prompt{value: &u.UserPassword, name: "user password", flags: []string{"--user-password"},
+		env: "SPLUNK_USER_PASSWORD", secret: true}
+	if err := c.ask(append(u.tokenPrompts(c), password)...); err != nil {
+		return err
+	}
+	cli, err := u.acsClient(c, u.StackName)
+	if err != nil {
+		return err
+	}
+	settings := u.settings()
+	settings.Password = u.UserPassword
+	err = cli.CreateUser(u.StackName, acs.User{Name: u.UserName, UserSettings: settings})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("created user '%s'\n", u.UserName)
+	return nil
+}
+
+type userList struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	stackFlags
+}
+
+func (u *userList) Run(c *context) error {
+	cli, err := u.acsClient(c, u.StackName)
+	if err != nil {
+		return err
+	}
+	users, err := cli.ListUsers(u.StackName)
+	if err != nil {
+		return err
+	}
+	printJSON(users)
+	return nil
+}
+
+type userGet struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	UserName  string `kong:"arg,help='the user'"`
+	stackFlags
+}
+
+func (u *userGet) Run(c *context) error {
+	cli, err := u.acsClient(c, u.StackName)
+	if err != nil {
+		return err
+	}
+	us, err := cli.DescribeUser(u.StackName, u.UserName)
+	if err != nil {
+		return err
+	}
+	printJSON(us)
+	return nil
+}
+
+type userUpdate struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	UserName  string `kong:"arg,help='the user'"`
+	userSettingsFlags
+	stackFlags
+}
+
+func (u *userUpdate) Run(c *context) error {
+	cli, err := u.acsClient(c, u.StackName)
+	if err != nil {
+		return err
+	}
+	err = cli.UpdateUser(u.StackName, u.UserName, u.settings())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("updated user '%s'\n", u.UserName)
+	return nil
+}
+
+type userDelete struct {
+	StackName string `kong:"arg,help='the splunk cloud stack'"`
+	UserName  string `kong:"arg,help='the user'"`
+	stackFlags
+}
+
+func (u *userDelete) Run(c *context) error {
+	cli, err := u.acsClient(c, u.StackName)
+	if err != nil {
+		return err
+	}
+	return cli.DeleteUser(u.StackName, u.UserName)
+}
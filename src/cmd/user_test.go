@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acsmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserCreate(t *testing.T) {
+	user := acs.User{Name: "ci", UserSettings: acs.UserSettings{Roles: []string{"user"}, Password: "changeme"}}
+	cli := &acsmock.Client{}
+	cli.On("CreateUser", "test-stack", user).Return(nil)
+
+	u := &userCreate{StackName: "test-stack", UserName: "ci", UserPassword: "changeme",
+		userSettingsFlags: userSettingsFlags{Roles: []string{"user"}}}
+	out := captureStdout(t, func() { assert.Nil(t, u.Run(&context{ACS: cli})) })
+	assert.Equal(t, "created user 'ci'\n", out)
+	cli.AssertExpectations(t)
+}
+
+func TestUserListGetUpdateDelete(t *testing.T) {
+	assert := assert.New(t)
+	user := acs.User{Name: "ci", UserSettings: acs.UserSettings{Roles: []string{"user"}}}
+	cli := &acsmock.Client{}
+	cli.On("ListUsers", "test-stack").Return([]acs.User{user}, nil)
+	cli.On("DescribeUser", "test-stack", "ci").Return(&user, nil)
+	cli.On("UpdateUser", "test-stack", "ci", acs.UserSettings{Email: "ci@example.com"}).Return(nil)
+	cli.On("DeleteUser", "test-stack", "ci").Return(nil)
+
+	out := captureStdout(t, func() { assert.Nil((&userList{StackName: "test-stack"}).Run(&context{ACS: cli})) })
+	assert.JSONEq(`[{"name": "ci", "roles": ["user"]}]`, out)
+	out = captureStdout(t, func() {
+		assert.Nil((&userGet{StackName: "test-stack", UserName: "ci"}).Run(&context{ACS: cli}))
+	})
+	assert.JSONEq(`{"name": "ci", "roles": ["user"]}`, out)
+	update := &userUpdate{StackName: "test-stack", UserName: "ci",
+		userSettingsFlags: userSettingsFlags{Email: "ci@example.com"}}
+	out = captureStdout(t, func() { assert.Nil(update.Run(&context{ACS: cli})) })
+	assert.Equal("updated user 'ci'\n", out)
+	assert.Nil((&userDelete{StackName: "test-stack", UserName: "ci"}).Run(&context{ACS: cli}))
+	cli.AssertExpectations(t)
+}
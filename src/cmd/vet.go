@@ -15,88 +15,69 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"github.com/AlecAivazis/survey/v2"
-	"github.com/splunk/acs-privateapps-demo/src/appinspect"
 	"io/ioutil"
-	"path/filepath"
-	"time"
+
+	"github.com/splunk/acs-privateapps-demo/src/appinspect"
+	"github.com/splunk/acs-privateapps-demo/src/pipeline"
 )
 
 type vet struct {
-	PackageFilePath   string `kong:"arg,help='the path to the app-package (tar.gz) file',type='path'"`
-	SplunkComUsername string `kong:"env='SPLUNK_COM_USERNAME',help='the splunkbase username'"`
-	SplunkComPassword string `kong:"env='SPLUNK_COM_PASSWORD',help='the splunkbase password'"`
-	JSONReportFile    string `kong:"help='the file to write the inspection report in json format',type='path'"`
-	Victoria          bool   `kong:"help='whether the stack is a Victora stack'"`
+	PackageFilePath string `kong:"arg,help='the path to the app-package (tar.gz) file',type='path'"`
+	splunkComFlags
+	JSONReportFile string `kong:"help='the file to write the inspection report in json format',type='path'"`
+	Victoria       bool   `kong:"help='whether the stack is a Victora stack'"`
+	AppinspectURL  string `kong:"env='APPINSPECT_URL',help='the appinspect url',default='https://appinspect.splunk.com/v1/app'"`
 }
 
+// statusPollInterval is how often vet checks on a pending inspection
+var statusPollInterval = pipeline.DefaultPollInterval
+
 func (v *vet) Run(c *context) error {
 
-	pf, err := ioutil.ReadFile(v.PackageFilePath)
+	credentials, err := v.credentials(c)
 	if err != nil {
 		return err
 	}
-	if v.SplunkComUsername == "" {
-		survey.AskOne(&survey.Input{
-			Message: "splunkbase username:",
-		}, &v.SplunkComUsername)
-	}
-	if v.SplunkComPassword == "" {
-		survey.AskOne(&survey.Password{
-			Message: "splunkbase password:",
-		}, &v.SplunkComPassword)
-		fmt.Println("")
-	}
-	cli := appinspect.New()
-	err = cli.Login(v.SplunkComUsername, v.SplunkComPassword)
+	pkg, err := openPackage(v.PackageFilePath)
 	if err != nil {
 		return err
 	}
-	submitRes, err := cli.Submit(filepath.Base(v.PackageFilePath), bytes.NewReader(pf), v.Victoria)
-	if err != nil {
-		return err
+	defer pkg.Close()
+	cli := c.AppInspect
+	if cli == nil {
+		cli = appinspect.NewWithURL(v.AppinspectURL, v.SplunkComLoginURL, c.appinspectOptions()...)
 	}
-	fmt.Printf("submitted app for inspection (requestId='%s')\n", submitRes.RequestID)
-
-	status, err := cli.Status(submitRes.RequestID)
-	if err != nil {
-		return err
+	if c.Tracer != nil {
+		cli = appinspect.Instrument(cli, c.Tracer)
 	}
-	if status.Status == "PROCESSING" || status.Status == "PREPARING" || status.Status == "PENDING" {
-		fmt.Printf("waiting for inspection to finish...\n")
-		for {
-			time.Sleep(2 * time.Second)
-			status, err = cli.Status(submitRes.RequestID)
-			if err != nil {
-				return err
-			}
-			if status.Status != "PROCESSING" {
-				break
-			}
-		}
+	res, err := pipeline.Vet(c.ctx(), pipeline.VetOptions{
+		Client:       cli,
+		Credentials:  credentials,
+		Package:      pkg,
+		Victoria:     v.Victoria,
+		PollInterval: statusPollInterval,
+		Report:       v.JSONReportFile != "",
+		Tracer:       c.Tracer,
+		Out:          c.progress(),
+	})
+	if res == nil {
+		return err
 	}
-	if status.Status == "SUCCESS" {
-		data, _ := json.MarshalIndent(status.Info, "", "    ")
+	if res.Status != nil && res.Status.Status == "SUCCESS" {
+		data, _ := json.MarshalIndent(res.Status.Info, "", "    ")
 		fmt.Printf("vetting completed, summary: \n%s\n", string(data))
-		if status.Info.Failure > 0 || status.Info.Error > 0 {
-			err = fmt.Errorf("vetting failed (failures=%d, errors=%d)", status.Info.Failure, status.Info.Error)
-		}
-	} else {
-		err = fmt.Errorf("vetting failed to complete (status='%s')", status.Status)
 	}
 
-	if v.JSONReportFile != "" {
-		report, e := cli.ReportJSON(submitRes.RequestID)
-		if e != nil {
-			fmt.Printf("failed to pull report: %s\n", e)
+	if v.JSONReportFile != "" && res.Status != nil {
+		if res.ReportErr != nil {
+			c.Logger.Warn("failed to pull report", "error", res.ReportErr)
 		}
-		data, _ := json.MarshalIndent(report, "", "    ")
-		e = ioutil.WriteFile(v.JSONReportFile, data, 0644)
+		data, _ := json.MarshalIndent(res.Report, "", "    ")
+		e := ioutil.WriteFile(v.JSONReportFile, data, 0644)
 		if e != nil {
-			fmt.Printf("failed to write report: %s\n", e)
+			c.Logger.Warn("failed to write report", "file", v.JSONReportFile, "error", e)
 		}
 	}
 	return err
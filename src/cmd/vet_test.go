@@ -0,0 +1,219 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/splunk/acs-privateapps-demo/src/appinspect"
+	"github.com/splunk/acs-privateapps-demo/src/appinspect/appinspectmock"
+	"github.com/splunk/acs-privateapps-demo/src/appinspect/appinspecttest"
+	"github.com/splunk/acs-privateapps-demo/src/telemetry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func init() {
+	statusPollInterval = time.Millisecond
+}
+
+// testPackage packages the testapp at the root of the repository like `make generate-app-package`
+func testPackage(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "app-package.tar.gz")
+	f, err := os.Create(path)
+	assert.Nil(t, err)
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	err = filepath.Walk("../../testapp", func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel("../..", p)
+		data, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if err = tw.WriteHeader(&tar.Header{Name: filepath.ToSlash(rel), Mode: 0644, Size: int64(len(data))}); err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, tw.Close())
+	assert.Nil(t, gz.Close())
+	return path
+}
+
+// testStackToken returns an unsigned JWT for sc_admin expiring in a day
+func testStackToken() string {
+	claims, _ := json.Marshal(map[string]interface{}{
+		"sub": "sc_admin",
+		"aud": "acs",
+		"exp": time.Now().Add(24 * time.Hour).Unix(),
+	})
+	return "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString(claims) + "."
+}
+
+func testSplunkComFlags(url string) splunkComFlags {
+	return splunkComFlags{
+		SplunkComUsername: "user",
+		SplunkComPassword: "pass",
+		SplunkComLoginURL: url + appinspecttest.LoginPath,
+	}
+}
+
+func TestVet(t *testing.T) {
+	assert := assert.New(t)
+	fake, srv := appinspecttest.NewServer()
+	defer srv.Close()
+	assert.Nil(fake.SetStatuses("PENDING", "PENDING", "PROCESSING", "SUCCESS"))
+
+	v := &vet{
+		PackageFilePath: testPackage(t),
+		splunkComFlags:  testSplunkComFlags(srv.URL),
+		JSONReportFile:  filepath.Join(t.TempDir(), "report.json"),
+		Victoria:        true,
+		AppinspectURL:   srv.URL + appinspecttest.AppInspectPath,
+	}
+	assert.Nil(v.Run(&context{}))
+
+	subs := fake.Submissions()
+	assert.Len(subs, 1)
+	assert.Equal("app-package.tar.gz", subs[0].Filename)
+	assert.Equal([]string{"private_victoria"}, subs[0].IncludedTags)
+
+	data, err := ioutil.ReadFile(v.JSONReportFile)
+	assert.Nil(err)
+	report := appinspect.ReportJSONResult{}
+	assert.Nil(json.Unmarshal(data, &report))
+	assert.Equal(subs[0].RequestID, report.RequestID)
+}
+
+func TestVetFailures(t *testing.T) {
+	fake, srv := appinspecttest.NewServer()
+	defer srv.Close()
+	report := appinspect.ReportJSONResult{}
+	report.Summary.Failure = 2
+	fake.SetReport(report)
+
+	v := &vet{
+		PackageFilePath: testPackage(t),
+		splunkComFlags:  testSplunkComFlags(srv.URL),
+		AppinspectURL:   srv.URL + appinspecttest.AppInspectPath,
+	}
+	err := v.Run(&context{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failures=2")
+}
+
+func TestVetInvalidCredentials(t *testing.T) {
+	fake, srv := appinspecttest.NewServer()
+	defer srv.Close()
+	fake.SetCredentials("user", "other")
+
+	v := &vet{
+		PackageFilePath: testPackage(t),
+		splunkComFlags:  testSplunkComFlags(srv.URL),
+		AppinspectURL:   srv.URL + appinspecttest.AppInspectPath,
+	}
+	assert.Error(t, v.Run(&context{}))
+	assert.Empty(t, fake.Submissions())
+}
+
+func TestVetTrace(t *testing.T) {
+	assert := assert.New(t)
+	fake, srv := appinspecttest.NewServer()
+	defer srv.Close()
+	assert.Nil(fake.SetStatuses("PENDING", "PROCESSING", "PROCESSING", "SUCCESS"))
+
+	v := &vet{
+		PackageFilePath: testPackage(t),
+		splunkComFlags:  testSplunkComFlags(srv.URL),
+		AppinspectURL:   srv.URL + appinspecttest.AppInspectPath,
+	}
+	c := &context{Tracer: telemetry.NewTracer("cloudCtl")}
+	assert.Nil(v.Run(c))
+
+	var names []string
+	parents := map[string]string{}
+	for _, s := range c.Tracer.Spans() {
+		names = append(names, s.Name)
+		parents[s.SpanID] = s.Name
+	}
+	assert.Equal([]string{"appinspect.Login", "appinspect.Submit", "appinspect.Status", "appinspect.Status",
+		"vet.queue", "appinspect.Status", "appinspect.Status", "vet.processing"}, names)
+	for _, s := range c.Tracer.Spans() {
+		if s.Name == "appinspect.Status" && s.ParentID != "" {
+			assert.Contains([]string{"vet.queue", "vet.processing"}, parents[s.ParentID])
+		}
+	}
+}
+
+func TestVetMock(t *testing.T) {
+	assert := assert.New(t)
+	pending := &appinspect.StatusResult{Status: "PROCESSING"}
+	done := &appinspect.StatusResult{Status: "SUCCESS"}
+	done.Info.Success = 10
+	cli := &appinspectmock.Client{}
+	cli.On("Login", "user", "pass").Return(nil)
+	cli.On("Submit", "app-package.tar.gz", mock.Anything, true).Return(&appinspect.SubmitResult{RequestID: "1"}, nil)
+	cli.On("Status", "1").Return(pending, nil).Once()
+	cli.On("Status", "1").Return(done, nil).Once()
+	cli.On("ReportJSON", "1").Return(&appinspect.ReportJSONResult{RequestID: "1"}, nil)
+
+	v := &vet{
+		PackageFilePath: testPackage(t),
+		splunkComFlags:  splunkComFlags{SplunkComUsername: "user", SplunkComPassword: "pass"},
+		JSONReportFile:  filepath.Join(t.TempDir(), "report.json"),
+		Victoria:        true,
+	}
+	assert.Nil(v.Run(&context{AppInspect: cli}))
+	assert.FileExists(v.JSONReportFile)
+	cli.AssertExpectations(t)
+}
+
+func TestVetMockErrors(t *testing.T) {
+	assert := assert.New(t)
+	cli := &appinspectmock.Client{}
+	cli.On("Login", "user", "pass").Return(nil)
+	cli.On("Submit", "app-package.tar.gz", mock.Anything, false).Return(&appinspect.SubmitResult{RequestID: "1"}, nil)
+	cli.On("Status", "1").Return(&appinspect.StatusResult{Status: "ERROR"}, nil).Once()
+	cli.On("Status", "1").Return(nil, errors.New("503 Service Unavailable")).Once()
+
+	v := &vet{
+		PackageFilePath: testPackage(t),
+		splunkComFlags:  splunkComFlags{SplunkComUsername: "user", SplunkComPassword: "pass"},
+	}
+	assert.EqualError(v.Run(&context{AppInspect: cli}), "vetting failed to complete (status='ERROR')")
+	assert.EqualError(v.Run(&context{AppInspect: cli}), "503 Service Unavailable")
+	cli.AssertExpectations(t)
+}
+
+func TestVetProgress(t *testing.T) {
+	assert := assert.New(t)
+	fake, srv := appinspecttest.NewServer()
+	defer srv.Close()
+	assert.Nil(fake.SetStatuses("PENDING", "SUCCESS"))
+	logger, err := newLogger("warn", "text", false)
+	assert.Nil(err)
+
+	// the progress is printed with the default log level
+	v := &vet{
+		PackageFilePath: testPackage(t),
+		splunkComFlags:  testSplunkComFlags(srv.URL),
+		AppinspectURL:   srv.URL + appinspecttest.AppInspectPath,
+	}
+	out := captureStdout(t, func() { assert.Nil(v.Run(&context{Logger: logger})) })
+	assert.Contains(out, "submitted app for inspection (requestId='")
+	assert.Contains(out, "waiting for inspection to finish...\n")
+	assert.Contains(out, "vetting completed, summary: \n")
+}
@@ -0,0 +1,112 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package httpclient builds the http clients of the acs and appinspect packages from the settings their
+// options collect: transport, tls configuration, proxy, user agent and hooks observing every request.
+package httpclient
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// RequestHook is called with every request before it is sent, it must not modify nor keep the request
+type RequestHook func(req *http.Request)
+
+// ResponseHook is called once every request completes with either its response or the error it failed
+// with, and how long it took. Hooks which read the response body must put back an equivalent one.
+type ResponseHook func(req *http.Request, resp *http.Response, err error, elapsed time.Duration)
+
+// Config holds the http settings of a client
+type Config struct {
+	// HTTPClient is the base of the client, its timeout, cookie jar and redirect policy are kept
+	HTTPClient *http.Client
+	// Transport replaces the transport of HTTPClient
+	Transport http.RoundTripper
+	// TLSConfig and Proxy apply to the transport when it is an *http.Transport
+	TLSConfig     *tls.Config
+	Proxy         *url.URL
+	UserAgent     string
+	RequestHooks  []RequestHook
+	ResponseHooks []ResponseHook
+}
+
+// Resty returns the resty client to build a client on
+func (c *Config) Resty() *resty.Client {
+	var r *resty.Client
+	if c.HTTPClient != nil {
+		hc := *c.HTTPClient
+		r = resty.NewWithClient(&hc)
+	} else {
+		r = resty.New()
+	}
+	r.SetTransport(c.RoundTripper())
+	if c.UserAgent != "" {
+		r.SetHeader("User-Agent", c.UserAgent)
+	}
+	return r
+}
+
+// RoundTripper returns the transport with the tls configuration, proxy and hooks applied
+func (c *Config) RoundTripper() http.RoundTripper {
+	t := c.Transport
+	if t == nil && c.HTTPClient != nil {
+		t = c.HTTPClient.Transport
+	}
+	if t == nil {
+		t = http.DefaultTransport
+	}
+	if ht, ok := t.(*http.Transport); ok && (c.TLSConfig != nil || c.Proxy != nil) {
+		ht = ht.Clone()
+		if c.TLSConfig != nil {
+			ht.TLSClientConfig = c.TLSConfig
+		}
+		if c.Proxy != nil {
+			ht.Proxy = http.ProxyURL(c.Proxy)
+		}
+		t = ht
+	}
+	if len(c.RequestHooks) == 0 && len(c.ResponseHooks) == 0 {
+		return t
+	}
+	return &hookTransport{
+		next:          t,
+		requestHooks:  c.RequestHooks,
+		responseHooks: c.ResponseHooks,
+	}
+}
+
+type hookTransport struct {
+	next          http.RoundTripper
+	requestHooks  []RequestHook
+	responseHooks []ResponseHook
+}
+
+// RoundTrip ...
+func (t *hookTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for _, h := range t.requestHooks {
+		h(req)
+	}
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start)
+	for _, h := range t.responseHooks {
+		h(req, resp, err, elapsed)
+	}
+	return resp, err
+}
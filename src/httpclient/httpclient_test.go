@@ -0,0 +1,127 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/splunk/acs-privateapps-demo/src/httpclient"
+	"github.com/splunk/acs-privateapps-demo/src/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHooksAndUserAgent(t *testing.T) {
+	assert := assert.New(t)
+	var userAgent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer srv.Close()
+
+	var requests []string
+	var status int
+	config := httpclient.Config{
+		UserAgent:    "cloudCtl",
+		RequestHooks: []httpclient.RequestHook{func(req *http.Request) { requests = append(requests, req.URL.Path) }},
+		ResponseHooks: []httpclient.ResponseHook{func(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+			status = resp.StatusCode
+		}},
+	}
+	_, err := config.Resty().R().Get(srv.URL + "/apps")
+	assert.Nil(err)
+	assert.Equal("cloudCtl", userAgent)
+	assert.Equal([]string{"/apps"}, requests)
+	assert.Equal(http.StatusTeapot, status)
+}
+
+func TestProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+	}))
+	defer proxy.Close()
+	proxyURL, _ := url.Parse(proxy.URL)
+
+	config := httpclient.Config{Proxy: proxyURL}
+	_, err := config.Resty().R().Get("http://acs.invalid/apps")
+	assert.Nil(t, err)
+	assert.Equal(t, "http://acs.invalid/apps", proxied)
+}
+
+func TestTracerRedacts(t *testing.T) {
+	assert := assert.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"1","token":"created-token"}`))
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	tracer := httpclient.NewTracer(&out)
+	config := httpclient.Config{
+		RequestHooks:  []httpclient.RequestHook{tracer.Request},
+		ResponseHooks: []httpclient.ResponseHook{tracer.Response},
+	}
+	resp, err := config.Resty().SetAuthToken("stack-token").R().SetHeader("Content-Type", "application/json").
+		SetBody(map[string]string{"user": "bob", "password": "secret"}).Post(srv.URL + "/tokens")
+	assert.Nil(err)
+	// the response is still there for the client once traced
+	assert.Contains(resp.String(), "created-token")
+
+	trace := out.String()
+	assert.Contains(trace, "> POST "+srv.URL+"/tokens")
+	assert.Contains(trace, "Authorization: "+httpclient.Redacted)
+	assert.Contains(trace, `"user":"bob"`)
+	assert.Contains(trace, "< HTTP/1.1 200 OK")
+	assert.NotContains(trace, "stack-token")
+	assert.NotContains(trace, "secret")
+	assert.NotContains(trace, "created-token")
+}
+
+func TestLogHook(t *testing.T) {
+	assert := assert.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-1")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	config := httpclient.Config{
+		ResponseHooks: []httpclient.ResponseHook{
+			httpclient.LogHook(logging.New(&out, logging.LevelInfo, logging.FormatText)),
+		},
+	}
+	_, err := config.Resty().SetAuthToken("stack-token").R().Get(srv.URL + "/test-stack/adminconfig/v2/apps?x=1")
+	assert.Nil(err)
+	assert.Contains(out.String(), `level=info msg="api call" method=GET path=/test-stack/adminconfig/v2/apps latencyMs=`)
+	assert.Contains(out.String(), "status=404 requestId=req-1")
+	assert.NotContains(out.String(), "stack-token")
+
+	// rejected requests are not logged at the default level of the commands
+	out.Reset()
+	config.ResponseHooks = []httpclient.ResponseHook{
+		httpclient.LogHook(logging.New(&out, logging.LevelWarn, logging.FormatText)),
+	}
+	_, err = config.Resty().R().Get(srv.URL + "/test-stack/adminconfig/v2/apps")
+	assert.Nil(err)
+	assert.Empty(out.String())
+}
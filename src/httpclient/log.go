@@ -0,0 +1,50 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/splunk/acs-privateapps-demo/src/logging"
+)
+
+// requestIDHeaders are the response headers the services return the id of a request in
+var requestIDHeaders = []string{"X-Request-Id", "X-Amzn-Requestid"}
+
+// LogHook logs the method, path, status, latency and request id of every request. Failed requests and
+// server errors are logged as errors, the rejected ones are logged as info like the others since the
+// commands report them already, i.e. a 404 is often expected.
+func LogHook(logger *logging.Logger) ResponseHook {
+	return func(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+		fields := []interface{}{"method", req.Method, "path", req.URL.Path, "latencyMs", elapsed.Milliseconds()}
+		if err != nil {
+			logger.Error("api call failed", append(fields, "error", err)...)
+			return
+		}
+		fields = append(fields, "status", resp.StatusCode)
+		for _, h := range requestIDHeaders {
+			if id := resp.Header.Get(h); id != "" {
+				fields = append(fields, "requestId", id)
+				break
+			}
+		}
+		level := logging.LevelInfo
+		if resp.StatusCode >= http.StatusInternalServerError {
+			level = logging.LevelError
+		}
+		logger.Log(level, "api call", fields...)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+
+	"github.com/splunk/acs-privateapps-demo/src/logging"
+)
+
+// Option sets up the Config of a client, the acs and appinspect packages take them through their WithHTTP
+// option
+type Option func(*Config)
+
+// WithHTTPClient builds the client on httpClient, keeping its timeout and transport
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Config) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithTransport makes the client send its requests through transport
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Config) {
+		c.Transport = transport
+	}
+}
+
+// WithTLSConfig sets the tls configuration of the client, i.e. to trust a private certificate authority
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Config) {
+		c.TLSConfig = config
+	}
+}
+
+// WithProxy sends the requests of the client through the proxy
+func WithProxy(proxy *url.URL) Option {
+	return func(c *Config) {
+		c.Proxy = proxy
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the requests of the client
+func WithUserAgent(userAgent string) Option {
+	return func(c *Config) {
+		c.UserAgent = userAgent
+	}
+}
+
+// WithRequestHook calls hook with every request of the client before it is sent
+func WithRequestHook(hook RequestHook) Option {
+	return func(c *Config) {
+		c.RequestHooks = append(c.RequestHooks, hook)
+	}
+}
+
+// WithResponseHook calls hook with the outcome of every request of the client
+func WithResponseHook(hook ResponseHook) Option {
+	return func(c *Config) {
+		c.ResponseHooks = append(c.ResponseHooks, hook)
+	}
+}
+
+// WithLogger logs every request of the client, see LogHook
+func WithLogger(logger *logging.Logger) Option {
+	return WithResponseHook(LogHook(logger))
+}
@@ -0,0 +1,99 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// Redacted replaces secrets in traces and recordings
+const Redacted = "REDACTED"
+
+// the headers and json fields holding secrets
+var (
+	secretHeaders = []string{"Authorization", "X-Splunk-Authorization", "X-Splunkbase-Authorization", "Cookie", "Set-Cookie"}
+	secretFields  = []string{"password", "token"}
+)
+
+// RedactHeader returns a copy of the header with the secrets replaced
+func RedactHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	redacted := header.Clone()
+	for _, h := range secretHeaders {
+		if redacted.Get(h) != "" {
+			redacted.Set(h, Redacted)
+		}
+	}
+	return redacted
+}
+
+// RedactBody replaces the secret fields of json bodies, other bodies are returned as they are
+func RedactBody(body []byte) string {
+	var v interface{}
+	if json.Unmarshal(body, &v) != nil {
+		return string(body)
+	}
+	data, err := json.Marshal(redactJSON(v))
+	if err != nil {
+		return string(body)
+	}
+	return string(data)
+}
+
+func redactJSON(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, field := range value {
+			if isSecretField(k) {
+				if _, ok := field.(string); ok {
+					value[k] = Redacted
+					continue
+				}
+			}
+			value[k] = redactJSON(field)
+		}
+	case []interface{}:
+		for i := range value {
+			value[i] = redactJSON(value[i])
+		}
+	}
+	return v
+}
+
+func isSecretField(name string) bool {
+	for _, f := range secretFields {
+		if strings.EqualFold(name, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPackage reports whether a request with the content type uploads an app package, whose content is never
+// traced nor recorded. The victoria install endpoint used to take the raw package labelled as a form.
+func IsPackage(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "multipart/form-data", "application/x-www-form-urlencoded", "application/gzip",
+		"application/x-gzip", "application/octet-stream":
+		return true
+	}
+	return false
+}
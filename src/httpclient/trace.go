@@ -0,0 +1,113 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxTracedBody is how much of a body is traced
+const maxTracedBody = 4096
+
+// Tracer writes a trace of every request and response with their secrets redacted, its Request and Response
+// methods are meant to be used as hooks
+type Tracer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewTracer writes traces to w
+func NewTracer(w io.Writer) *Tracer {
+	return &Tracer{w: w}
+}
+
+// Request traces a request about to be sent
+func (t *Tracer) Request(req *http.Request) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "> %s %s\n", req.Method, req.URL)
+	writeHeader(&buf, "> ", RedactHeader(req.Header))
+	if IsPackage(req.Header.Get("Content-Type")) {
+		if req.ContentLength > 0 {
+			fmt.Fprintf(&buf, "> <app package, %d bytes>\n", req.ContentLength)
+		}
+	} else if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil && body != nil {
+			data, _ := ioutil.ReadAll(body)
+			body.Close()
+			writeBody(&buf, "> ", data)
+		}
+	}
+	t.write(buf.Bytes())
+}
+
+// Response traces the response to a request, or the error it failed with
+func (t *Tracer) Response(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+	var buf bytes.Buffer
+	elapsed = elapsed.Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(&buf, "< %s %s failed after %s: %s\n", req.Method, req.URL, elapsed, err)
+		t.write(buf.Bytes())
+		return
+	}
+	fmt.Fprintf(&buf, "< %s %s %s\n", resp.Proto, resp.Status, elapsed)
+	writeHeader(&buf, "< ", RedactHeader(resp.Header))
+	if resp.Body != nil {
+		data, e := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+		if e != nil {
+			fmt.Fprintf(&buf, "< <failed to read body: %s>\n", e)
+		}
+		writeBody(&buf, "< ", data)
+	}
+	t.write(buf.Bytes())
+}
+
+func (t *Tracer) write(trace []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.w.Write(trace)
+}
+
+func writeHeader(buf *bytes.Buffer, prefix string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, v := range header[name] {
+			fmt.Fprintf(buf, "%s%s: %s\n", prefix, name, v)
+		}
+	}
+}
+
+func writeBody(buf *bytes.Buffer, prefix string, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	body := RedactBody(data)
+	if len(body) > maxTracedBody {
+		body = fmt.Sprintf("%s... <%d bytes>", body[:maxTracedBody], len(body))
+	}
+	fmt.Fprintf(buf, "%s\n%s\n", prefix, body)
+}
@@ -0,0 +1,295 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// mockgen generates a testify mock of an interface, so the mocks follow the interfaces they implement.
+// It only needs the standard library:
+//
+//	go run ../internal/mockgen -source client.go -interface Client -package acsmock -out acsmock/client.go
+//
+// The types of the package declaring the interface are qualified with its name, the other types must be
+// qualified already, i.e. io.Reader.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const modulePath = "github.com/splunk/acs-privateapps-demo"
+
+func main() {
+	source := flag.String("source", "", "the go file declaring the interface")
+	iface := flag.String("interface", "", "the name of the interface")
+	mockName := flag.String("mock", "", "the name of the mock type, the name of the interface by default")
+	pkg := flag.String("package", "", "the package of the mock")
+	out := flag.String("out", "", "the file to write the mock to")
+	flag.Parse()
+	if *source == "" || *iface == "" || *pkg == "" || *out == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *mockName == "" {
+		*mockName = *iface
+	}
+	code, err := generate(*source, *iface, *mockName, *pkg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = os.MkdirAll(filepath.Dir(*out), 0755); err != nil {
+		log.Fatal(err)
+	}
+	if err = ioutil.WriteFile(*out, code, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generate(source, ifaceName, mockName, pkg string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, source, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	iface := findInterface(file, ifaceName)
+	if iface == nil {
+		return nil, fmt.Errorf("interface %s not found in %s", ifaceName, source)
+	}
+	importPath, err := packageImportPath(source)
+	if err != nil {
+		return nil, err
+	}
+	srcPkg := file.Name.Name
+
+	// imports of the source file, by name
+	imports := map[string]string{}
+	for _, imp := range file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		name := filepath.Base(path)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		imports[name] = path
+	}
+	used := map[string]string{"mock": "github.com/stretchr/testify/mock"}
+
+	var methods bytes.Buffer
+	for _, field := range iface.Methods.List {
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 {
+			return nil, fmt.Errorf("embedded interfaces are not supported")
+		}
+		qualify(fn, srcPkg, func(name string) {
+			if name == srcPkg {
+				used[name] = importPath
+			} else if path, ok := imports[name]; ok {
+				used[name] = path
+			}
+		})
+		writeMethod(&methods, fset, mockName, field.Names[0].Name, fn)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by mockgen from %s. DO NOT EDIT.\n\n", filepath.Base(source))
+	fmt.Fprintf(&buf, "package %s\n\nimport (\n", pkg)
+	// the standard library first, like goimports
+	names := make([]string, 0, len(used))
+	for name := range used {
+		names = append(names, name)
+	}
+	std := func(path string) bool { return !strings.Contains(strings.SplitN(path, "/", 2)[0], ".") }
+	sort.Slice(names, func(i, j int) bool {
+		a, b := used[names[i]], used[names[j]]
+		if std(a) != std(b) {
+			return std(a)
+		}
+		return a < b
+	})
+	for i, name := range names {
+		if i > 0 && std(used[names[i-1]]) && !std(used[name]) {
+			buf.WriteString("\n")
+		}
+		if filepath.Base(used[name]) == name {
+			fmt.Fprintf(&buf, "\t%q\n", used[name])
+		} else {
+			fmt.Fprintf(&buf, "\t%s %q\n", name, used[name])
+		}
+	}
+	fmt.Fprintf(&buf, ")\n\n// %s is a mock of %s.%s\ntype %s struct {\n\tmock.Mock\n}\n\n", mockName, srcPkg,
+		ifaceName, mockName)
+	fmt.Fprintf(&buf, "var _ %s.%s = (*%s)(nil)\n", srcPkg, ifaceName, mockName)
+	buf.Write(methods.Bytes())
+	return format.Source(buf.Bytes())
+}
+
+func findInterface(file *ast.File, name string) *ast.InterfaceType {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if it, ok := ts.Type.(*ast.InterfaceType); ok && ts.Name.Name == name {
+				return it
+			}
+		}
+	}
+	return nil
+}
+
+// packageImportPath derives the import path of the package of the source file from the module path
+func packageImportPath(source string) (string, error) {
+	dir, err := filepath.Abs(filepath.Dir(source))
+	if err != nil {
+		return "", err
+	}
+	for root := dir; ; root = filepath.Dir(root) {
+		if _, err := os.Stat(filepath.Join(root, "go.mod")); err == nil {
+			rel, err := filepath.Rel(root, dir)
+			if err != nil {
+				return "", err
+			}
+			return modulePath + "/" + filepath.ToSlash(rel), nil
+		}
+		if filepath.Dir(root) == root {
+			return "", fmt.Errorf("no go.mod found above %s", dir)
+		}
+	}
+}
+
+// qualify prefixes the exported types of the source package with its name and reports the packages used
+func qualify(fn *ast.FuncType, srcPkg string, use func(pkg string)) {
+	var fix func(expr ast.Expr) ast.Expr
+	fix = func(expr ast.Expr) ast.Expr {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			if unicode.IsUpper(rune(e.Name[0])) {
+				use(srcPkg)
+				return &ast.SelectorExpr{X: ast.NewIdent(srcPkg), Sel: ast.NewIdent(e.Name)}
+			}
+		case *ast.SelectorExpr:
+			use(e.X.(*ast.Ident).Name)
+		case *ast.StarExpr:
+			e.X = fix(e.X)
+		case *ast.ArrayType:
+			e.Elt = fix(e.Elt)
+		case *ast.MapType:
+			e.Key = fix(e.Key)
+			e.Value = fix(e.Value)
+		case *ast.Ellipsis:
+			e.Elt = fix(e.Elt)
+		case *ast.FuncType:
+			fixFields(e.Params, fix)
+			fixFields(e.Results, fix)
+		}
+		return expr
+	}
+	fixFields(fn.Params, fix)
+	fixFields(fn.Results, fix)
+}
+
+func fixFields(fields *ast.FieldList, fix func(ast.Expr) ast.Expr) {
+	if fields == nil {
+		return
+	}
+	for _, f := range fields.List {
+		f.Type = fix(f.Type)
+	}
+}
+
+type param struct {
+	name string
+	typ  string
+}
+
+func params(fset *token.FileSet, fields *ast.FieldList, prefix string) []param {
+	var ps []param
+	if fields == nil {
+		return ps
+	}
+	for _, f := range fields.List {
+		typ := exprString(fset, f.Type)
+		if len(f.Names) == 0 {
+			ps = append(ps, param{name: fmt.Sprintf("%s%d", prefix, len(ps)), typ: typ})
+			continue
+		}
+		for _, n := range f.Names {
+			name := n.Name
+			if name == "_" || name == "m" || name == "ret" {
+				name = fmt.Sprintf("%s%d", prefix, len(ps))
+			}
+			ps = append(ps, param{name: name, typ: typ})
+		}
+	}
+	return ps
+}
+
+func exprString(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, fset, expr)
+	return buf.String()
+}
+
+func writeMethod(buf *bytes.Buffer, fset *token.FileSet, mockName, name string, fn *ast.FuncType) {
+	in := params(fset, fn.Params, "arg")
+	out := params(fset, fn.Results, "r")
+
+	var sig, args, results []string
+	for _, p := range in {
+		sig = append(sig, p.name+" "+p.typ)
+		arg := p.name
+		if strings.HasPrefix(p.typ, "...") {
+			arg += "..."
+		}
+		args = append(args, arg)
+	}
+	for _, p := range out {
+		results = append(results, p.typ)
+	}
+	result := strings.Join(results, ", ")
+	if len(results) > 1 {
+		result = "(" + result + ")"
+	}
+
+	fmt.Fprintf(buf, "\n// %s ...\nfunc (m *%s) %s(%s) %s {\n", name, mockName, name, strings.Join(sig, ", "), result)
+	called := fmt.Sprintf("m.Called(%s)", strings.Join(args, ", "))
+	if len(out) == 0 {
+		fmt.Fprintf(buf, "\t%s\n}\n", called)
+		return
+	}
+	fmt.Fprintf(buf, "\tret := %s\n", called)
+	var returns []string
+	for i, p := range out {
+		if p.typ == "error" {
+			returns = append(returns, fmt.Sprintf("ret.Error(%d)", i))
+			continue
+		}
+		fmt.Fprintf(buf, "\tr%d, _ := ret.Get(%d).(%s)\n", i, i, p.typ)
+		returns = append(returns, fmt.Sprintf("r%d", i))
+	}
+	fmt.Fprintf(buf, "\treturn %s\n}\n", strings.Join(returns, ", "))
+}
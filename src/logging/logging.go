@@ -0,0 +1,276 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logging is a small structured logger writing leveled records as text (logfmt) or json lines.
+// Values of the secret fields, i.e. password or token, are redacted.
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level of a record
+type Level int
+
+// the levels from the most to the least verbose
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+// Levels lists the names of the levels, i.e. for the help of a flag
+var Levels = strings.Join(levelNames, ",")
+
+func (l Level) String() string {
+	if l < LevelDebug || l > LevelError {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel returns the level with the name
+func ParseLevel(name string) (Level, error) {
+	for i, n := range levelNames {
+		if strings.EqualFold(name, n) {
+			return Level(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level '%s', expected one of %s", name, Levels)
+}
+
+// Format of the records
+type Format string
+
+// the formats records are written in
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+// Formats lists the names of the formats, i.e. for the help of a flag
+var Formats = strings.Join([]string{string(FormatText), string(FormatJSON)}, ",")
+
+// ParseFormat returns the format with the name
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case FormatText, FormatJSON:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown log format '%s', expected one of %s", name, Formats)
+}
+
+// Redacted replaces the values of secret fields
+const Redacted = "REDACTED"
+
+// Logger writes the records at or above its level. Fields are passed as alternating keys and values.
+type Logger struct {
+	out    *output
+	level  Level
+	fields []interface{}
+}
+
+// output is shared by a logger and the ones derived from it with With
+type output struct {
+	mu     sync.Mutex
+	w      io.Writer
+	format Format
+	now    func() time.Time
+}
+
+// New logs the records at or above level to w
+func New(w io.Writer, level Level, format Format) *Logger {
+	return &Logger{
+		out:   &output{w: w, format: format, now: time.Now},
+		level: level,
+	}
+}
+
+// Discard is a logger writing nothing, which is what a nil Logger does too
+var Discard = New(ioutil.Discard, LevelError+1, FormatText)
+
+// With returns a logger adding the fields to every record
+func (l *Logger) With(fields ...interface{}) *Logger {
+	if l == nil {
+		return nil
+	}
+	return &Logger{
+		out:    l.out,
+		level:  l.level,
+		fields: append(append([]interface{}{}, l.fields...), fields...),
+	}
+}
+
+// Enabled reports whether records of the level are written
+func (l *Logger) Enabled(level Level) bool {
+	return l != nil && level >= l.level
+}
+
+// Debug logs a record for troubleshooting
+func (l *Logger) Debug(msg string, fields ...interface{}) {
+	l.Log(LevelDebug, msg, fields...)
+}
+
+// Info logs a record of something that happened
+func (l *Logger) Info(msg string, fields ...interface{}) {
+	l.Log(LevelInfo, msg, fields...)
+}
+
+// Warn logs a record of something which may need attention
+func (l *Logger) Warn(msg string, fields ...interface{}) {
+	l.Log(LevelWarn, msg, fields...)
+}
+
+// Error logs a record of something which failed
+func (l *Logger) Error(msg string, fields ...interface{}) {
+	l.Log(LevelError, msg, fields...)
+}
+
+// Log logs a record at the level
+func (l *Logger) Log(level Level, msg string, fields ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	keys, values := pairs(append(append([]interface{}{}, l.fields...), fields...))
+	var buf bytes.Buffer
+	t := l.out.now().UTC().Format(time.RFC3339Nano)
+	if l.out.format == FormatJSON {
+		buf.WriteByte('{')
+		writeJSONField(&buf, "time", t)
+		writeJSONField(&buf, "level", level.String())
+		writeJSONField(&buf, "msg", msg)
+		for i, k := range keys {
+			writeJSONField(&buf, k, jsonValue(values[i]))
+		}
+		buf.Truncate(buf.Len() - 1)
+		buf.WriteByte('}')
+	} else {
+		fmt.Fprintf(&buf, "time=%s level=%s msg=%s", t, level, quote(msg))
+		for i, k := range keys {
+			fmt.Fprintf(&buf, " %s=%s", k, quote(textValue(values[i])))
+		}
+	}
+	buf.WriteByte('\n')
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	l.out.w.Write(buf.Bytes())
+}
+
+// writeJSONField writes "key":value, keeping the fields in the order they were logged
+func writeJSONField(buf *bytes.Buffer, key string, value interface{}) {
+	writeJSON(buf, key)
+	buf.WriteByte(':')
+	if err := writeJSON(buf, value); err != nil {
+		writeJSON(buf, fmt.Sprint(value))
+	}
+	buf.WriteByte(',')
+}
+
+func writeJSON(buf *bytes.Buffer, v interface{}) error {
+	var data bytes.Buffer
+	enc := json.NewEncoder(&data)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	buf.Write(bytes.TrimSuffix(data.Bytes(), []byte("\n")))
+	return nil
+}
+
+// pairs splits the fields in keys and values, redacting secrets. A key without a value is logged under
+// the "!BADKEY" key, later fields override the earlier ones with the same key.
+func pairs(fields []interface{}) ([]string, []interface{}) {
+	index := map[string]int{}
+	var keys []string
+	var values []interface{}
+	for i := 0; i < len(fields); i += 2 {
+		key, ok := fields[i].(string)
+		var value interface{}
+		if !ok || i+1 == len(fields) {
+			key, value = "!BADKEY", fields[i]
+			i--
+		} else {
+			value = fields[i+1]
+		}
+		if isSecret(key) {
+			value = Redacted
+		}
+		if j, ok := index[key]; ok {
+			values[j] = value
+			continue
+		}
+		index[key] = len(keys)
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+	return keys, values
+}
+
+// secretKeys are the keys of the fields whose values are redacted, matched case insensitively. Fields merely
+// mentioning a secret, e.g. tokenName, are logged.
+var secretKeys = []string{"token", "password", "authorization", "x-splunk-authorization"}
+
+func isSecret(key string) bool {
+	for _, secret := range secretKeys {
+		if strings.EqualFold(key, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case error:
+		return value.Error()
+	case time.Duration:
+		return value.Seconds()
+	case fmt.Stringer:
+		return value.String()
+	}
+	return v
+}
+
+func textValue(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case error:
+		return value.Error()
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case []string:
+		return strings.Join(value, ",")
+	}
+	return fmt.Sprint(v)
+}
+
+// quote quotes values which would otherwise not read back as a single one
+func quote(s string) string {
+	if s == "" || strings.ContainsAny(s, " =\"\t\r\n\\") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,91 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/splunk/acs-privateapps-demo/src/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2021, 12, 1, 10, 0, 0, 0, time.UTC)
+
+func TestText(t *testing.T) {
+	var out bytes.Buffer
+	l := logging.New(&out, logging.LevelInfo, logging.FormatText)
+	logging.SetNow(l, now)
+
+	l.Debug("not logged")
+	l.With("stack", "test-stack").Info("app installed", "app", "my app", "error", errors.New("none"))
+	assert.Equal(t, "time=2021-12-01T10:00:00Z level=info msg=\"app installed\" stack=test-stack app=\"my app\" "+
+		"error=none\n", out.String())
+}
+
+func TestJSON(t *testing.T) {
+	var out bytes.Buffer
+	l := logging.New(&out, logging.LevelDebug, logging.FormatJSON)
+	logging.SetNow(l, now)
+
+	l.Warn("api call", "path", "/<stack>/apps", "status", 429, "latency", 1500*time.Millisecond)
+	assert.Equal(t, `{"time":"2021-12-01T10:00:00Z","level":"warn","msg":"api call","path":"/<stack>/apps",`+
+		`"status":429,"latency":1.5}`+"\n", out.String())
+}
+
+func TestRedactsSecrets(t *testing.T) {
+	var out bytes.Buffer
+	l := logging.New(&out, logging.LevelDebug, logging.FormatText)
+	l.Info("login", "user", "bob", "password", "secret", "Token", "eyJ", "X-Splunk-Authorization", "Bearer eyJ")
+	assert.Contains(t, out.String(), "user=bob password=REDACTED Token=REDACTED X-Splunk-Authorization=REDACTED")
+	assert.NotContains(t, out.String(), "secret")
+	assert.NotContains(t, out.String(), "eyJ")
+}
+
+func TestKeepsFieldsNamingSecrets(t *testing.T) {
+	var out bytes.Buffer
+	l := logging.New(&out, logging.LevelDebug, logging.FormatText)
+	l.Info("token created", "tokenName", "ci", "passwordPolicy", "strict")
+	assert.Contains(t, out.String(), "tokenName=ci passwordPolicy=strict")
+}
+
+func TestBadKey(t *testing.T) {
+	var out bytes.Buffer
+	l := logging.New(&out, logging.LevelDebug, logging.FormatText)
+	l.Info("odd", "key", "value", "dangling")
+	assert.Contains(t, out.String(), "key=value !BADKEY=dangling")
+}
+
+func TestNil(t *testing.T) {
+	var l *logging.Logger
+	l.With("key", "value").Error("not logged")
+	assert.False(t, l.Enabled(logging.LevelError))
+}
+
+func TestParse(t *testing.T) {
+	level, err := logging.ParseLevel("WARN")
+	assert.Nil(t, err)
+	assert.Equal(t, logging.LevelWarn, level)
+	_, err = logging.ParseLevel("verbose")
+	assert.Error(t, err)
+
+	format, err := logging.ParseFormat("json")
+	assert.Nil(t, err)
+	assert.Equal(t, logging.FormatJSON, format)
+	_, err = logging.ParseFormat("xml")
+	assert.Error(t, err)
+}
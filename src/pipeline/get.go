@@ -0,0 +1,63 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+)
+
+// GetOptions configure Get
+type GetOptions struct {
+	// Client is the acs client of the stack
+	Client acs.Client
+	Stack  string
+	// App is the app to describe, all the apps of the stack are listed when it is empty
+	App string
+}
+
+// GetResult holds either the described app or the apps of the stack
+type GetResult struct {
+	App  *acs.App
+	Apps []acs.App
+}
+
+// Object returns the described app, or the list of apps when none was asked for
+func (r *GetResult) Object() interface{} {
+	if r.App != nil {
+		return r.App
+	}
+	return r.Apps
+}
+
+// Get describes an app installed on a stack, or lists all of them. Nothing is requested once ctx is done.
+func Get(ctx context.Context, opts GetOptions) (*GetResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error while getting apps: %s", err)
+	}
+	var err error
+	res := &GetResult{}
+	if opts.App == "" {
+		res.Apps, err = opts.Client.ListApps(opts.Stack)
+	} else {
+		res.App, err = opts.Client.DescribeApp(opts.Stack, opts.App)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,40 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acsmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	assert := assert.New(t)
+	name := "testapp"
+	cli := &acsmock.Client{}
+	cli.On("ListApps", "test-stack").Return([]acs.App{{Name: &name}}, nil)
+	cli.On("DescribeApp", "test-stack", "testapp").Return(&acs.App{Name: &name}, nil)
+	cli.On("DescribeApp", "test-stack", "missing").Return(nil, errors.New("404 Not Found"))
+
+	res, err := Get(context.Background(), GetOptions{Client: cli, Stack: "test-stack"})
+	assert.Nil(err)
+	assert.Equal([]acs.App{{Name: &name}}, res.Object())
+	res, err = Get(context.Background(), GetOptions{Client: cli, Stack: "test-stack", App: "testapp"})
+	assert.Nil(err)
+	assert.Equal(&acs.App{Name: &name}, res.Object())
+	_, err = Get(context.Background(), GetOptions{Client: cli, Stack: "test-stack", App: "missing"})
+	assert.EqualError(err, "404 Not Found")
+	cli.AssertExpectations(t)
+}
+
+func TestGetCanceled(t *testing.T) {
+	cli := &acsmock.Client{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Get(ctx, GetOptions{Client: cli, Stack: "test-stack"})
+	assert.EqualError(t, err, "error while getting apps: context canceled")
+	cli.AssertExpectations(t)
+}
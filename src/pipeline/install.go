@@ -0,0 +1,173 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/appinspect"
+	"github.com/splunk/acs-privateapps-demo/src/logging"
+	"github.com/splunk/acs-privateapps-demo/src/telemetry"
+	"github.com/splunk/acs-privateapps-demo/src/upload"
+)
+
+// DefaultRestartTimeout is how long Install waits for the stack to restart unless told otherwise
+const DefaultRestartTimeout = 30 * time.Minute
+
+// InstallOptions configure Install
+type InstallOptions struct {
+	// Client is the acs client of the stack
+	Client acs.Client
+	Stack  string
+	// Package is the app package to install
+	Package *upload.Package
+	// Token is the splunk.com token the install is authorized with, Install logs in with Authenticator and
+	// Credentials when it is empty
+	Token         string
+	Authenticator appinspect.Authenticator
+	Credentials   Credentials
+	// RestartIfNeeded restarts the stack if the install requires it and waits up to RestartTimeout, or
+	// DefaultRestartTimeout when 0, for it to come back
+	RestartIfNeeded bool
+	RestartTimeout  time.Duration
+	// Indexes are created before the app is installed, unless the stack has an index of the same name
+	Indexes     []acs.Index
+	Maintenance MaintenanceOptions
+	// Logger and Tracer record the install, nil records nothing
+	Logger *logging.Logger
+	Tracer *telemetry.Tracer
+	// Out receives the progress messages, nil discards them
+	Out io.Writer
+}
+
+// validate checks the options Install cannot do without
+func (o *InstallOptions) validate() error {
+	switch {
+	case o.Client == nil:
+		return fmt.Errorf("error while installing app: no acs client given")
+	case o.Package == nil:
+		return fmt.Errorf("error while installing app: no package given")
+	case o.Token == "" && o.Authenticator == nil:
+		return fmt.Errorf("error while installing app: neither a token nor an authenticator given")
+	}
+	return nil
+}
+
+// InstallResult is the outcome of an install
+type InstallResult struct {
+	// RestartRequired reports whether the stack required a restart for the app to take effect, it is false when
+	// that could not be checked, which fails the install with RestartIfNeeded
+	RestartRequired bool
+	// Restarted reports whether the stack was restarted
+	Restarted bool
+}
+
+// Install installs an app package on a stack, outside of its maintenance windows, and restarts the stack
+// when needed and asked to. The waits for a maintenance window to end and for the stack to restart stop when
+// ctx is done.
+func Install(ctx context.Context, opts InstallOptions) (*InstallResult, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	out := output(opts.Out)
+	cli := opts.Client
+	if err := CheckMaintenance(ctx, cli, opts.Stack, opts.Maintenance, opts.Logger, out); err != nil {
+		return nil, err
+	}
+	token := opts.Token
+	if token == "" {
+		var err error
+		token, err = Authenticate(opts.Authenticator, opts.Credentials, opts.Tracer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := createIndexes(cli, opts.Stack, opts.Indexes, out); err != nil {
+		return nil, err
+	}
+	err := cli.InstallApp(opts.Stack, token, opts.Package.Name(), opts.Package)
+	if err != nil {
+		return nil, err
+	}
+
+	// the app is installed at this point, failing to tell whether it needs a restart only fails the install
+	// when it was asked to restart the stack if needed
+	res := &InstallResult{}
+	res.RestartRequired, err = cli.RestartRequired(opts.Stack)
+	if err != nil && opts.RestartIfNeeded {
+		return res, fmt.Errorf("error while checking whether the stack requires a restart: %s", err)
+	}
+	if err != nil {
+		opts.Logger.Warn("could not check whether the stack requires a restart", "stack", opts.Stack, "error", err)
+		fmt.Fprintf(out, "app installed, could not check whether the stack requires a restart: %s\n", err)
+		return res, nil
+	}
+	if !res.RestartRequired {
+		return res, nil
+	}
+	if !opts.RestartIfNeeded {
+		fmt.Fprintf(out, "app installed, the stack requires a restart for it to take effect\n")
+		return res, nil
+	}
+	fmt.Fprintf(out, "app installed, restarting the stack...\n")
+	if err = cli.RestartStack(opts.Stack); err != nil {
+		return res, err
+	}
+	timeout := opts.RestartTimeout
+	if timeout == 0 {
+		timeout = DefaultRestartTimeout
+	}
+	if err = cli.WaitForRestart(ctx, opts.Stack, timeout); err != nil {
+		return res, err
+	}
+	res.Restarted = true
+	fmt.Fprintf(out, "stack restarted\n")
+	return res, nil
+}
+
+// createIndexes creates the indexes the stack does not have yet
+func createIndexes(cli acs.Client, stack string, indexes []acs.Index, out io.Writer) error {
+	if len(indexes) == 0 {
+		return nil
+	}
+	existing, err := cli.ListIndexes(stack)
+	if err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for _, index := range existing {
+		names[index.Name] = true
+	}
+	for _, index := range indexes {
+		if names[index.Name] {
+			continue
+		}
+		err = cli.CreateIndex(stack, index)
+		if errors.Is(err, acs.ErrIndexExists) {
+			// created since it was listed
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "created index '%s'\n", index.Name)
+	}
+	return nil
+}
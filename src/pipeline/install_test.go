@@ -0,0 +1,168 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acsmock"
+	"github.com/splunk/acs-privateapps-demo/src/appinspect"
+	"github.com/splunk/acs-privateapps-demo/src/appinspect/appinspectmock"
+	"github.com/splunk/acs-privateapps-demo/src/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInstall(t *testing.T) {
+	assert := assert.New(t)
+	auth := &appinspectmock.Authenticator{}
+	login := &appinspect.AuthenticateResult{}
+	login.Data.Token = "token"
+	auth.On("Authenticate", "user", "pass").Return(login, nil)
+	cli := &acsmock.Client{}
+	cli.On("ListMaintenanceWindows", "test-stack").Return([]acs.MaintenanceWindow{}, nil)
+	cli.On("InstallApp", "test-stack", "token", "app.tar.gz", mock.Anything).Return(nil)
+	cli.On("RestartRequired", "test-stack").Return(true, nil)
+	cli.On("RestartStack", "test-stack").Return(nil)
+	cli.On("WaitForRestart", mock.Anything, "test-stack", DefaultRestartTimeout).Return(nil)
+
+	res, err := Install(context.Background(), InstallOptions{Client: cli, Stack: "test-stack", Package: testPackage(), Authenticator: auth,
+		Credentials: testCredentials, RestartIfNeeded: true})
+	assert.Nil(err)
+	assert.Equal(&InstallResult{RestartRequired: true, Restarted: true}, res)
+	cli.AssertExpectations(t)
+	auth.AssertExpectations(t)
+}
+
+func TestInstallWithToken(t *testing.T) {
+	assert := assert.New(t)
+	cli := &acsmock.Client{}
+	cli.On("ListMaintenanceWindows", "test-stack").Return([]acs.MaintenanceWindow{}, nil)
+	cli.On("InstallApp", "test-stack", "token", "app.tar.gz", mock.Anything).Return(nil)
+	cli.On("RestartRequired", "test-stack").Return(true, nil)
+
+	res, err := Install(context.Background(), InstallOptions{Client: cli, Stack: "test-stack", Package: testPackage(), Token: "token"})
+	assert.Nil(err)
+	assert.Equal(&InstallResult{RestartRequired: true}, res)
+	cli.AssertExpectations(t)
+}
+
+func TestInstallRestartRequiredFailure(t *testing.T) {
+	assert := assert.New(t)
+	cli := &acsmock.Client{}
+	cli.On("ListMaintenanceWindows", "test-stack").Return([]acs.MaintenanceWindow{}, nil)
+	cli.On("InstallApp", "test-stack", "token", "app.tar.gz", mock.Anything).Return(nil)
+	cli.On("RestartRequired", "test-stack").Return(false, errors.New("503 Service Unavailable"))
+	var logs bytes.Buffer
+	opts := InstallOptions{Client: cli, Stack: "test-stack", Package: testPackage(), Token: "token",
+		Logger: logging.New(&logs, logging.LevelInfo, logging.FormatText)}
+
+	// a plain install only warns
+	res, err := Install(context.Background(), opts)
+	assert.Nil(err)
+	assert.Equal(&InstallResult{}, res)
+	assert.Contains(logs.String(), "could not check whether the stack requires a restart")
+
+	// an install asked to restart the stack if needed can't report success without knowing
+	opts.RestartIfNeeded = true
+	res, err = Install(context.Background(), opts)
+	assert.EqualError(err, "error while checking whether the stack requires a restart: 503 Service Unavailable")
+	assert.Equal(&InstallResult{}, res)
+	cli.AssertNotCalled(t, "RestartStack", "test-stack")
+	cli.AssertExpectations(t)
+}
+
+func TestInstallRestartFailure(t *testing.T) {
+	assert := assert.New(t)
+	cli := &acsmock.Client{}
+	cli.On("ListMaintenanceWindows", "test-stack").Return([]acs.MaintenanceWindow{}, nil)
+	cli.On("InstallApp", "test-stack", "token", "app.tar.gz", mock.Anything).Return(nil)
+	cli.On("RestartRequired", "test-stack").Return(true, nil)
+	cli.On("RestartStack", "test-stack").Return(nil)
+	cli.On("WaitForRestart", mock.Anything, "test-stack", time.Minute).Return(errors.New("timed out"))
+
+	res, err := Install(context.Background(), InstallOptions{Client: cli, Stack: "test-stack", Package: testPackage(), Token: "token",
+		RestartIfNeeded: true, RestartTimeout: time.Minute})
+	assert.EqualError(err, "timed out")
+	assert.Equal(&InstallResult{RestartRequired: true}, res)
+	cli.AssertExpectations(t)
+}
+
+func TestInstallIndexFailure(t *testing.T) {
+	cli := &acsmock.Client{}
+	cli.On("ListMaintenanceWindows", "test-stack").Return([]acs.MaintenanceWindow{}, nil)
+	cli.On("ListIndexes", "test-stack").Return([]acs.Index{}, nil)
+	cli.On("CreateIndex", "test-stack", acs.Index{Name: "web", Datatype: "event"}).Return(errors.New("400 Bad Request"))
+
+	// the app is not installed without its indexes
+	_, err := Install(context.Background(), InstallOptions{Client: cli, Stack: "test-stack", Package: testPackage(),
+		Token: "token", Indexes: []acs.Index{{Name: "web", Datatype: "event"}}})
+	assert.EqualError(t, err, "400 Bad Request")
+	cli.AssertNotCalled(t, "InstallApp", "test-stack", "token", "app.tar.gz", mock.Anything)
+	cli.AssertExpectations(t)
+}
+
+func TestInstallIndexCreatedMeanwhile(t *testing.T) {
+	cli := &acsmock.Client{}
+	cli.On("ListMaintenanceWindows", "test-stack").Return([]acs.MaintenanceWindow{}, nil)
+	cli.On("ListIndexes", "test-stack").Return([]acs.Index{}, nil)
+	cli.On("CreateIndex", "test-stack", acs.Index{Name: "web", Datatype: "event"}).
+		Return(fmt.Errorf("error while creating index: %w: web", acs.ErrIndexExists))
+	cli.On("InstallApp", "test-stack", "token", "app.tar.gz", mock.Anything).Return(nil)
+	cli.On("RestartRequired", "test-stack").Return(false, nil)
+
+	// an index which exists by the time it is created is not an error
+	_, err := Install(context.Background(), InstallOptions{Client: cli, Stack: "test-stack", Package: testPackage(),
+		Token: "token", Indexes: []acs.Index{{Name: "web", Datatype: "event"}}})
+	assert.Nil(t, err)
+	cli.AssertExpectations(t)
+}
+
+func TestInstallMaintenance(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+	cli := &acsmock.Client{}
+	cli.On("ListMaintenanceWindows", "test-stack").
+		Return([]acs.MaintenanceWindow{{StartTime: now.Add(-time.Hour), EndTime: now.Add(time.Hour)}}, nil)
+
+	_, err := Install(context.Background(), InstallOptions{Client: cli, Stack: "test-stack", Package: testPackage(), Token: "token"})
+	var maintenance *MaintenanceError
+	assert.True(errors.As(err, &maintenance))
+	assert.WithinDuration(now.Add(time.Hour), maintenance.End, time.Second)
+	cli.AssertExpectations(t)
+}
+
+func TestInstallMaintenanceWaitCanceled(t *testing.T) {
+	now := time.Now()
+	cli := &acsmock.Client{}
+	cli.On("ListMaintenanceWindows", "test-stack").
+		Return([]acs.MaintenanceWindow{{StartTime: now.Add(-time.Hour), EndTime: now.Add(time.Hour)}}, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := Install(ctx, InstallOptions{Client: cli, Stack: "test-stack", Package: testPackage(), Token: "token",
+		Maintenance: MaintenanceOptions{Wait: 2 * time.Hour}})
+	assert.EqualError(t, err, "error while waiting for the maintenance window to end: context deadline exceeded")
+	cli.AssertNotCalled(t, "InstallApp", "test-stack", "token", "app.tar.gz", mock.Anything)
+	cli.AssertExpectations(t)
+}
+
+func TestInstallWithoutAuthentication(t *testing.T) {
+	cli := &acsmock.Client{}
+
+	_, err := Install(context.Background(), InstallOptions{Client: cli, Stack: "test-stack", Package: testPackage()})
+	assert.EqualError(t, err, "error while installing app: neither a token nor an authenticator given")
+	cli.AssertNotCalled(t, "ListMaintenanceWindows", "test-stack")
+	cli.AssertNotCalled(t, "InstallApp", "test-stack", "", "app.tar.gz", mock.Anything)
+}
+
+func TestInstallInvalidOptions(t *testing.T) {
+	_, err := Install(context.Background(), InstallOptions{Stack: "test-stack", Package: testPackage(), Token: "token"})
+	assert.EqualError(t, err, "error while installing app: no acs client given")
+	_, err = Install(context.Background(), InstallOptions{Client: &acsmock.Client{}, Stack: "test-stack", Token: "token"})
+	assert.EqualError(t, err, "error while installing app: no package given")
+}
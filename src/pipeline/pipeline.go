@@ -0,0 +1,119 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pipeline runs the steps of the app deploy pipeline of cloudCtl, vetting an app package with
+// appinspect, installing it on a stack, getting and uninstalling apps, so other go tools can embed the
+// same workflow. The steps never prompt, the callers pass in the clients, the credentials and the
+// confirmations.
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/appinspect"
+	"github.com/splunk/acs-privateapps-demo/src/logging"
+	"github.com/splunk/acs-privateapps-demo/src/telemetry"
+)
+
+// output returns where the progress messages of a step are written, nil discards them
+func output(w io.Writer) io.Writer {
+	if w == nil {
+		return ioutil.Discard
+	}
+	return w
+}
+
+// Credentials are the splunk.com credentials the appinspect and splunkbase calls authenticate with
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Authenticate logs in to splunk.com and returns the token of the session
+func Authenticate(auth appinspect.Authenticator, credentials Credentials, tracer *telemetry.Tracer) (string, error) {
+	span := tracer.Start("appinspect.Authenticate")
+	res, err := auth.Authenticate(credentials.Username, credentials.Password)
+	span.Finish(err)
+	if err != nil {
+		return "", err
+	}
+	return res.Data.Token, nil
+}
+
+// MaintenanceOptions configure how the steps changing a stack handle its maintenance windows
+type MaintenanceOptions struct {
+	// Ignore runs the step even when the stack is in a maintenance window
+	Ignore bool
+	// Wait is how long to wait for an ongoing maintenance window to end, 0 fails right away
+	Wait time.Duration
+}
+
+// MaintenanceError is returned by the steps changing a stack which is in a maintenance window
+type MaintenanceError struct {
+	End time.Time
+}
+
+func (e *MaintenanceError) Error() string {
+	return fmt.Sprintf("stack is in a maintenance window until %s", e.End.Format(time.RFC3339))
+}
+
+// CheckMaintenance fails, or waits up to opts.Wait, while the stack is in a maintenance window. The wait stops
+// when ctx is done. With opts.Ignore it only warns, about the window and about failing to list the windows.
+func CheckMaintenance(ctx context.Context, cli acs.Client, stack string, opts MaintenanceOptions,
+	logger *logging.Logger, out io.Writer) error {
+	deadline := time.Now().Add(opts.Wait)
+	for {
+		windows, err := cli.ListMaintenanceWindows(stack)
+		if err != nil && opts.Ignore {
+			logger.Warn("could not list the maintenance windows", "stack", stack, "error", err)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		active := acs.ActiveMaintenanceWindow(windows, now)
+		if active == nil {
+			return nil
+		}
+		if opts.Ignore {
+			logger.Warn("stack is in a maintenance window", "stack", stack, "end", active.EndTime.Format(time.RFC3339))
+			return nil
+		}
+		if active.EndTime.After(deadline) {
+			return &MaintenanceError{End: active.EndTime}
+		}
+		fmt.Fprintf(output(out), "waiting for the maintenance window to end at %s...\n", active.EndTime.Format(time.RFC3339))
+		if err = sleep(ctx, active.EndTime.Sub(now)); err != nil {
+			return fmt.Errorf("error while waiting for the maintenance window to end: %s", err)
+		}
+	}
+}
+
+// sleep waits for d, or returns the error of ctx if it is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
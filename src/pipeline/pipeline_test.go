@@ -0,0 +1,30 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs/acsmock"
+	"github.com/splunk/acs-privateapps-demo/src/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckMaintenanceListingFailure(t *testing.T) {
+	assert := assert.New(t)
+	cli := &acsmock.Client{}
+	cli.On("ListMaintenanceWindows", "test-stack").Return(nil, errors.New("403 Forbidden"))
+	var logs bytes.Buffer
+	logger := logging.New(&logs, logging.LevelWarn, logging.FormatText)
+
+	err := CheckMaintenance(context.Background(), cli, "test-stack", MaintenanceOptions{}, logger, nil)
+	assert.EqualError(err, "403 Forbidden")
+	assert.Empty(logs.String())
+
+	// the override runs the step anyway, warning that the windows are unknown
+	err = CheckMaintenance(context.Background(), cli, "test-stack", MaintenanceOptions{Ignore: true}, logger, nil)
+	assert.Nil(err)
+	assert.Contains(logs.String(), `level=warn msg="could not list the maintenance windows" stack=test-stack error="403 Forbidden"`)
+	cli.AssertExpectations(t)
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/logging"
+)
+
+// ErrUninstallAborted is returned by Uninstall when the confirmation was declined
+var ErrUninstallAborted = errors.New("uninstall aborted")
+
+// UninstallOptions configure Uninstall
+type UninstallOptions struct {
+	// Client is the acs client of the stack
+	Client acs.Client
+	Stack  string
+	App    string
+	// ProtectedApps are never uninstalled
+	ProtectedApps []string
+	Maintenance   MaintenanceOptions
+	// Confirm is asked right before the app is uninstalled, which is aborted unless it returns true. The
+	// app is uninstalled without asking when it is nil.
+	Confirm func(stack string, app *acs.App) (bool, error)
+	// Logger records the uninstall, nil records nothing
+	Logger *logging.Logger
+	// Out receives the progress messages, nil discards them
+	Out io.Writer
+}
+
+// UninstallResult is the outcome of an uninstall
+type UninstallResult struct {
+	// App is the app as it was before it was uninstalled
+	App *acs.App
+}
+
+// Uninstall removes a private app from a stack, refusing protected and splunkbase apps. The wait for a
+// maintenance window to end stops when ctx is done.
+func Uninstall(ctx context.Context, opts UninstallOptions) (*UninstallResult, error) {
+	for _, app := range opts.ProtectedApps {
+		if app == opts.App {
+			return nil, fmt.Errorf("app '%s' is protected and cannot be uninstalled", opts.App)
+		}
+	}
+
+	cli := opts.Client
+	app, err := cli.DescribeApp(opts.Stack, opts.App)
+	if err != nil {
+		return nil, err
+	}
+	if !app.IsPrivate() {
+		return nil, fmt.Errorf("app '%s' is not a private app (splunkbaseID='%s')", opts.App, *app.SplunkbaseID)
+	}
+	if err = CheckMaintenance(ctx, cli, opts.Stack, opts.Maintenance, opts.Logger, opts.Out); err != nil {
+		return nil, err
+	}
+
+	if opts.Confirm != nil {
+		confirmed, err := opts.Confirm(opts.Stack, app)
+		if err != nil {
+			return nil, err
+		}
+		if !confirmed {
+			return nil, ErrUninstallAborted
+		}
+	}
+	if err = cli.UninstallApp(opts.Stack, opts.App); err != nil {
+		return nil, err
+	}
+	return &UninstallResult{App: app}, nil
+}
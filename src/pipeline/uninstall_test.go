@@ -0,0 +1,49 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/acs"
+	"github.com/splunk/acs-privateapps-demo/src/acs/acsmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUninstall(t *testing.T) {
+	assert := assert.New(t)
+	name := "testapp"
+	cli := &acsmock.Client{}
+	cli.On("DescribeApp", "test-stack", "testapp").Return(&acs.App{Name: &name}, nil)
+	cli.On("ListMaintenanceWindows", "test-stack").Return([]acs.MaintenanceWindow{}, nil)
+	cli.On("UninstallApp", "test-stack", "testapp").Return(nil).Once()
+
+	confirm := func(answer bool) func(string, *acs.App) (bool, error) {
+		return func(stack string, app *acs.App) (bool, error) {
+			assert.Equal("test-stack", stack)
+			assert.Equal("testapp", *app.Name)
+			return answer, nil
+		}
+	}
+	opts := UninstallOptions{Client: cli, Stack: "test-stack", App: "testapp", Confirm: confirm(false)}
+	_, err := Uninstall(context.Background(), opts)
+	assert.Equal(ErrUninstallAborted, err)
+
+	opts.Confirm = confirm(true)
+	res, err := Uninstall(context.Background(), opts)
+	assert.Nil(err)
+	assert.Equal("testapp", *res.App.Name)
+	cli.AssertExpectations(t)
+}
+
+func TestUninstallRefused(t *testing.T) {
+	assert := assert.New(t)
+	id := "1621"
+	cli := &acsmock.Client{}
+	cli.On("DescribeApp", "test-stack", "Splunk_SA_CIM").Return(&acs.App{SplunkbaseID: &id}, nil)
+
+	_, err := Uninstall(context.Background(), UninstallOptions{Client: cli, Stack: "test-stack", App: "search", ProtectedApps: []string{"search"}})
+	assert.EqualError(err, "app 'search' is protected and cannot be uninstalled")
+	_, err = Uninstall(context.Background(), UninstallOptions{Client: cli, Stack: "test-stack", App: "Splunk_SA_CIM"})
+	assert.EqualError(err, "app 'Splunk_SA_CIM' is not a private app (splunkbaseID='1621')")
+	cli.AssertExpectations(t)
+}
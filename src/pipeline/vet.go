@@ -0,0 +1,155 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/splunk/acs-privateapps-demo/src/appinspect"
+	"github.com/splunk/acs-privateapps-demo/src/telemetry"
+	"github.com/splunk/acs-privateapps-demo/src/upload"
+)
+
+// DefaultPollInterval is how often Vet checks on a pending inspection unless told otherwise
+const DefaultPollInterval = 2 * time.Second
+
+// VetOptions configure Vet
+type VetOptions struct {
+	// Client is the appinspect client the package is inspected with
+	Client      appinspect.ClientInterface
+	Credentials Credentials
+	// Package is the app package to inspect, its sha256 is known once it was submitted
+	Package *upload.Package
+	// Victoria inspects the package for a victoria stack
+	Victoria bool
+	// PollInterval is how often the status of a pending inspection is checked, DefaultPollInterval when 0
+	PollInterval time.Duration
+	// Report fetches the json report of the inspection once it finished
+	Report bool
+	// Tracer records the time the inspection spent queued and processing, nil records nothing
+	Tracer *telemetry.Tracer
+	// Out receives the progress messages, nil discards them
+	Out io.Writer
+}
+
+// validate checks the options Vet cannot do without
+func (o *VetOptions) validate() error {
+	switch {
+	case o.Client == nil:
+		return fmt.Errorf("error while vetting app: no appinspect client given")
+	case o.Package == nil:
+		return fmt.Errorf("error while vetting app: no package given")
+	}
+	return nil
+}
+
+// VetResult is the outcome of an inspection
+type VetResult struct {
+	RequestID string
+	SHA256    string
+	Status    *appinspect.StatusResult
+	// Report is the json report of the inspection when VetOptions.Report is set and it could be fetched
+	Report *appinspect.ReportJSONResult
+	// ReportErr is why the report could not be fetched
+	ReportErr error
+}
+
+// Passed reports whether the inspection completed without failures or errors
+func (r *VetResult) Passed() bool {
+	return r.Status != nil && r.Status.Status == "SUCCESS" && r.Status.Info.Failure == 0 && r.Status.Info.Error == 0
+}
+
+// inspectionPending reports whether an inspection with the status is yet to finish
+func inspectionPending(status string) bool {
+	return status == "PROCESSING" || status == "PREPARING" || status == "PENDING"
+}
+
+// inspectionPhase names the span of the pending inspection step with the status
+func inspectionPhase(status string) string {
+	if status == "PROCESSING" {
+		return "vet.processing"
+	}
+	return "vet.queue"
+}
+
+// Vet submits an app package to appinspect and waits for the inspection to finish. The result is returned
+// along with the error once the package was submitted, i.e. when the inspection found failures. The wait for
+// the inspection stops when ctx is done.
+func Vet(ctx context.Context, opts VetOptions) (*VetResult, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	out := output(opts.Out)
+	interval := opts.PollInterval
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+	cli := opts.Client
+	err := cli.Login(opts.Credentials.Username, opts.Credentials.Password)
+	if err != nil {
+		return nil, err
+	}
+	submitRes, err := cli.Submit(opts.Package.Name(), opts.Package, opts.Victoria)
+	if err != nil {
+		return nil, err
+	}
+	res := &VetResult{RequestID: submitRes.RequestID, SHA256: opts.Package.SHA256()}
+	fmt.Fprintf(out, "submitted app for inspection (requestId='%s', sha256='%s')\n", res.RequestID, res.SHA256)
+
+	status, err := cli.Status(res.RequestID)
+	if err != nil {
+		return res, err
+	}
+	if inspectionPending(status.Status) {
+		fmt.Fprintf(out, "waiting for inspection to finish...\n")
+		// the time spent queued and processing is traced as separate steps
+		phase := inspectionPhase(status.Status)
+		span := opts.Tracer.Start(phase, "request.id", res.RequestID)
+		for inspectionPending(status.Status) {
+			if err = sleep(ctx, interval); err != nil {
+				err = fmt.Errorf("error while waiting for the inspection to finish: %s", err)
+				span.Finish(err)
+				return res, err
+			}
+			status, err = cli.Status(res.RequestID)
+			if err != nil {
+				span.Finish(err)
+				return res, err
+			}
+			if next := inspectionPhase(status.Status); inspectionPending(status.Status) && next != phase {
+				span.Finish(nil)
+				phase = next
+				span = opts.Tracer.Start(phase, "request.id", res.RequestID)
+			}
+		}
+		span.Finish(nil)
+	}
+	res.Status = status
+	if status.Status == "SUCCESS" {
+		if status.Info.Failure > 0 || status.Info.Error > 0 {
+			err = fmt.Errorf("vetting failed (failures=%d, errors=%d)", status.Info.Failure, status.Info.Error)
+		}
+	} else {
+		err = fmt.Errorf("vetting failed to complete (status='%s')", status.Status)
+	}
+
+	if opts.Report {
+		res.Report, res.ReportErr = cli.ReportJSON(res.RequestID)
+	}
+	return res, err
+}
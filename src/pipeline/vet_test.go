@@ -0,0 +1,102 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/splunk/acs-privateapps-demo/src/appinspect"
+	"github.com/splunk/acs-privateapps-demo/src/appinspect/appinspectmock"
+	"github.com/splunk/acs-privateapps-demo/src/telemetry"
+	"github.com/splunk/acs-privateapps-demo/src/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testCredentials = Credentials{Username: "user", Password: "pass"}
+
+func testPackage() *upload.Package {
+	return upload.New("app.tar.gz", strings.NewReader("package"), 7)
+}
+
+func TestVet(t *testing.T) {
+	assert := assert.New(t)
+	done := &appinspect.StatusResult{Status: "SUCCESS"}
+	done.Info.Success = 10
+	cli := &appinspectmock.Client{}
+	cli.On("Login", "user", "pass").Return(nil)
+	cli.On("Submit", "app.tar.gz", mock.Anything, true).Return(&appinspect.SubmitResult{RequestID: "1"}, nil)
+	cli.On("Status", "1").Return(&appinspect.StatusResult{Status: "PENDING"}, nil).Once()
+	cli.On("Status", "1").Return(&appinspect.StatusResult{Status: "PROCESSING"}, nil).Once()
+	cli.On("Status", "1").Return(done, nil).Once()
+	cli.On("ReportJSON", "1").Return(nil, errors.New("404 Not Found"))
+
+	var out bytes.Buffer
+	tracer := telemetry.NewTracer("test")
+	res, err := Vet(context.Background(), VetOptions{Client: cli, Credentials: testCredentials,
+		Package: testPackage(), Victoria: true, PollInterval: time.Millisecond, Report: true, Tracer: tracer, Out: &out})
+	assert.Nil(err)
+	assert.True(res.Passed())
+	assert.Equal("1", res.RequestID)
+	assert.Len(res.SHA256, 64)
+	assert.EqualError(res.ReportErr, "404 Not Found")
+	assert.Contains(out.String(), "waiting for inspection to finish")
+	var names []string
+	for _, s := range tracer.Spans() {
+		names = append(names, s.Name)
+	}
+	assert.Equal([]string{"vet.queue", "vet.processing"}, names)
+	cli.AssertExpectations(t)
+}
+
+func TestVetFailures(t *testing.T) {
+	assert := assert.New(t)
+	failed := &appinspect.StatusResult{Status: "SUCCESS"}
+	failed.Info.Failure = 2
+	cli := &appinspectmock.Client{}
+	cli.On("Login", "user", "pass").Return(nil)
+	cli.On("Submit", "app.tar.gz", mock.Anything, false).Return(&appinspect.SubmitResult{RequestID: "1"}, nil)
+	cli.On("Status", "1").Return(failed, nil)
+
+	res, err := Vet(context.Background(), VetOptions{Client: cli, Credentials: testCredentials, Package: testPackage()})
+	assert.EqualError(err, "vetting failed (failures=2, errors=0)")
+	assert.False(res.Passed())
+	assert.Nil(res.Report)
+	cli.AssertExpectations(t)
+}
+
+func TestVetLoginFailure(t *testing.T) {
+	cli := &appinspectmock.Client{}
+	cli.On("Login", "user", "pass").Return(errors.New("401 Unauthorized"))
+
+	res, err := Vet(context.Background(), VetOptions{Client: cli, Credentials: testCredentials, Package: testPackage()})
+	assert.Nil(t, res)
+	assert.EqualError(t, err, "401 Unauthorized")
+	cli.AssertExpectations(t)
+}
+
+func TestVetInvalidOptions(t *testing.T) {
+	_, err := Vet(context.Background(), VetOptions{Credentials: testCredentials, Package: testPackage()})
+	assert.EqualError(t, err, "error while vetting app: no appinspect client given")
+	_, err = Vet(context.Background(), VetOptions{Client: &appinspectmock.Client{}, Credentials: testCredentials})
+	assert.EqualError(t, err, "error while vetting app: no package given")
+}
+
+func TestVetCanceled(t *testing.T) {
+	assert := assert.New(t)
+	cli := &appinspectmock.Client{}
+	cli.On("Login", "user", "pass").Return(nil)
+	cli.On("Submit", "app.tar.gz", mock.Anything, false).Return(&appinspect.SubmitResult{RequestID: "1"}, nil)
+	cli.On("Status", "1").Return(&appinspect.StatusResult{Status: "PENDING"}, nil).Once()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res, err := Vet(ctx, VetOptions{Client: cli, Credentials: testCredentials, Package: testPackage(),
+		PollInterval: time.Hour})
+	assert.EqualError(err, "error while waiting for the inspection to finish: context canceled")
+	assert.Equal("1", res.RequestID)
+	cli.AssertExpectations(t)
+}
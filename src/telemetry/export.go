@@ -0,0 +1,231 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scopeName identifies the instrumentation in OTLP exports
+const scopeName = "github.com/splunk/acs-privateapps-demo/src/telemetry"
+
+// Trace is the content of a trace file
+type Trace struct {
+	Service string   `json:"service"`
+	TraceID string   `json:"traceId"`
+	Spans   []Span   `json:"spans"`
+	Metrics []Metric `json:"metrics"`
+}
+
+// Trace returns the finished spans and their metrics
+func (t *Tracer) Trace() *Trace {
+	if t == nil {
+		return nil
+	}
+	return &Trace{
+		Service: t.service,
+		TraceID: t.traceID,
+		Spans:   t.Spans(),
+		Metrics: t.Metrics(),
+	}
+}
+
+// WriteFile writes the trace to a json file, i.e. to keep it as a ci artifact
+func (t *Tracer) WriteFile(path string) error {
+	data, err := json.MarshalIndent(t.Trace(), "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// ExportOTLP sends the spans and metrics to the OTLP/HTTP collector at endpoint, i.e. http://localhost:4318,
+// in the json encoding
+func (t *Tracer) ExportOTLP(client *http.Client, endpoint string) error {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	trace := t.Trace()
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if err := postJSON(client, endpoint+"/v1/traces", otlpTraces(trace)); err != nil {
+		return err
+	}
+	return postJSON(client, endpoint+"/v1/metrics", otlpMetrics(trace, t.started, t.now()))
+}
+
+func postJSON(client *http.Client, url string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("error while exporting to %s: %s", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("error while exporting to %s: %s: %s", url, resp.Status, string(body))
+	}
+	return nil
+}
+
+// the OTLP json encoding, see https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+
+type otlpValue map[string]interface{}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+func otlpAttributes(attributes map[string]interface{}) []otlpAttribute {
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	otlp := make([]otlpAttribute, 0, len(keys))
+	for _, k := range keys {
+		var v otlpValue
+		switch value := attributes[k].(type) {
+		case bool:
+			v = otlpValue{"boolValue": value}
+		case int:
+			v = otlpValue{"intValue": strconv.Itoa(value)}
+		case int64:
+			v = otlpValue{"intValue": strconv.FormatInt(value, 10)}
+		case float64:
+			v = otlpValue{"doubleValue": value}
+		default:
+			v = otlpValue{"stringValue": fmt.Sprint(value)}
+		}
+		otlp = append(otlp, otlpAttribute{Key: k, Value: v})
+	}
+	return otlp
+}
+
+func nanos(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func otlpResource(service string) map[string]interface{} {
+	return map[string]interface{}{
+		"attributes": otlpAttributes(map[string]interface{}{"service.name": service}),
+	}
+}
+
+func otlpTraces(trace *Trace) map[string]interface{} {
+	spans := make([]map[string]interface{}, 0, len(trace.Spans))
+	for _, s := range trace.Spans {
+		status := map[string]interface{}{"code": 1}
+		if s.Error != "" {
+			status = map[string]interface{}{"code": 2, "message": s.Error}
+		}
+		span := map[string]interface{}{
+			"traceId":           s.TraceID,
+			"spanId":            s.SpanID,
+			"name":              s.Name,
+			"kind":              1,
+			"startTimeUnixNano": nanos(s.Start),
+			"endTimeUnixNano":   nanos(s.End),
+			"attributes":        otlpAttributes(s.Attributes),
+			"status":            status,
+		}
+		if s.ParentID != "" {
+			span["parentSpanId"] = s.ParentID
+		}
+		spans = append(spans, span)
+	}
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource": otlpResource(trace.Service),
+			"scopeSpans": []interface{}{map[string]interface{}{
+				"scope": map[string]interface{}{"name": scopeName},
+				"spans": spans,
+			}},
+		}},
+	}
+}
+
+// otlpMetrics exports the metrics as a span.duration histogram in milliseconds and a span.errors counter,
+// both with a data point per span name
+func otlpMetrics(trace *Trace, start, now time.Time) map[string]interface{} {
+	bounds := make([]float64, len(DurationBuckets))
+	for i, b := range DurationBuckets {
+		bounds[i] = float64(b.Milliseconds())
+	}
+	var durations, errors []interface{}
+	for _, m := range trace.Metrics {
+		attributes := otlpAttributes(map[string]interface{}{"span.name": m.Name})
+		buckets := make([]string, len(m.Buckets))
+		for i, b := range m.Buckets {
+			buckets[i] = strconv.FormatInt(b, 10)
+		}
+		durations = append(durations, map[string]interface{}{
+			"attributes":        attributes,
+			"startTimeUnixNano": nanos(start),
+			"timeUnixNano":      nanos(now),
+			"count":             strconv.FormatInt(m.Count, 10),
+			"sum":               float64(m.SumMs),
+			"min":               float64(m.MinMs),
+			"max":               float64(m.MaxMs),
+			"bucketCounts":      buckets,
+			"explicitBounds":    bounds,
+		})
+		errors = append(errors, map[string]interface{}{
+			"attributes":        attributes,
+			"startTimeUnixNano": nanos(start),
+			"timeUnixNano":      nanos(now),
+			"asInt":             strconv.FormatInt(m.Errors, 10),
+		})
+	}
+	const cumulative = 2
+	return map[string]interface{}{
+		"resourceMetrics": []interface{}{map[string]interface{}{
+			"resource": otlpResource(trace.Service),
+			"scopeMetrics": []interface{}{map[string]interface{}{
+				"scope": map[string]interface{}{"name": scopeName},
+				"metrics": []interface{}{
+					map[string]interface{}{
+						"name": "span.duration",
+						"unit": "ms",
+						"histogram": map[string]interface{}{
+							"aggregationTemporality": cumulative,
+							"dataPoints":             durations,
+						},
+					},
+					map[string]interface{}{
+						"name": "span.errors",
+						"unit": "1",
+						"sum": map[string]interface{}{
+							"aggregationTemporality": cumulative,
+							"isMonotonic":            true,
+							"dataPoints":             errors,
+						},
+					},
+				},
+			}},
+		}},
+	}
+}
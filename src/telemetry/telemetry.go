@@ -0,0 +1,211 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package telemetry records spans and metrics of the steps of a deployment, i.e. uploading a package,
+// waiting on AppInspect or installing on a stack, and exports them to a json file or an OTLP collector.
+//
+// It is meant for command line tools: a span started while another one is open becomes its child, so
+// spans of a Tracer must be started and ended from a single goroutine. A nil Tracer records nothing.
+package telemetry
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DurationBuckets are the upper bounds of the buckets of the duration histograms
+var DurationBuckets = []time.Duration{
+	10 * time.Millisecond, 50 * time.Millisecond, 100 * time.Millisecond, 250 * time.Millisecond,
+	500 * time.Millisecond, time.Second, 2500 * time.Millisecond, 5 * time.Second, 10 * time.Second,
+	30 * time.Second, time.Minute, 5 * time.Minute,
+}
+
+// Tracer records the spans of a single trace along with the metrics of their durations
+type Tracer struct {
+	mu      sync.Mutex
+	service string
+	traceID string
+	started time.Time
+	open    []*Span
+	spans   []*Span
+	metrics map[string]*Metric
+	now     func() time.Time
+}
+
+// NewTracer records the spans of the service
+func NewTracer(service string) *Tracer {
+	t := &Tracer{
+		service: service,
+		traceID: newID(16),
+		metrics: map[string]*Metric{},
+		now:     time.Now,
+	}
+	t.started = t.now()
+	return t
+}
+
+// Span is a timed step of a trace
+type Span struct {
+	tracer     *Tracer
+	TraceID    string                 `json:"traceId"`
+	SpanID     string                 `json:"spanId"`
+	ParentID   string                 `json:"parentId,omitempty"`
+	Name       string                 `json:"name"`
+	Start      time.Time              `json:"start"`
+	End        time.Time              `json:"end"`
+	DurationMs int64                  `json:"durationMs"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+// Metric aggregates the durations of the spans with the same name
+type Metric struct {
+	Name   string        `json:"name"`
+	Count  int64         `json:"count"`
+	Errors int64         `json:"errors"`
+	Sum    time.Duration `json:"-"`
+	Min    time.Duration `json:"-"`
+	Max    time.Duration `json:"-"`
+	// Buckets counts the durations up to each of DurationBuckets, plus the ones above the last bucket
+	Buckets []int64 `json:"buckets"`
+	SumMs   int64   `json:"sumMs"`
+	MinMs   int64   `json:"minMs"`
+	MaxMs   int64   `json:"maxMs"`
+}
+
+// Start opens a span, child of the innermost open span. Attributes are passed as alternating keys and values.
+func (t *Tracer) Start(name string, attributes ...interface{}) *Span {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := &Span{
+		tracer:  t,
+		TraceID: t.traceID,
+		SpanID:  newID(8),
+		Name:    name,
+		Start:   t.now(),
+	}
+	if len(t.open) > 0 {
+		s.ParentID = t.open[len(t.open)-1].SpanID
+	}
+	for i := 0; i+1 < len(attributes); i += 2 {
+		if k, ok := attributes[i].(string); ok {
+			s.SetAttribute(k, attributes[i+1])
+		}
+	}
+	t.open = append(t.open, s)
+	return s
+}
+
+// SetAttribute annotates the span
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	if s.Attributes == nil {
+		s.Attributes = map[string]interface{}{}
+	}
+	s.Attributes[key] = value
+}
+
+// Finish closes the span, err is the outcome of the step it timed
+func (s *Span) Finish(err error) {
+	if s == nil {
+		return
+	}
+	t := s.tracer
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !s.End.IsZero() {
+		return
+	}
+	s.End = t.now()
+	s.DurationMs = s.End.Sub(s.Start).Milliseconds()
+	if err != nil {
+		s.Error = err.Error()
+	}
+	for i := len(t.open) - 1; i >= 0; i-- {
+		if t.open[i] == s {
+			t.open = append(t.open[:i], t.open[i+1:]...)
+			break
+		}
+	}
+	t.spans = append(t.spans, s)
+	t.record(s)
+}
+
+func (t *Tracer) record(s *Span) {
+	m, ok := t.metrics[s.Name]
+	if !ok {
+		m = &Metric{Name: s.Name, Buckets: make([]int64, len(DurationBuckets)+1)}
+		t.metrics[s.Name] = m
+	}
+	d := s.End.Sub(s.Start)
+	if m.Count == 0 || d < m.Min {
+		m.Min = d
+	}
+	if d > m.Max {
+		m.Max = d
+	}
+	m.Count++
+	m.Sum += d
+	if s.Error != "" {
+		m.Errors++
+	}
+	i := sort.Search(len(DurationBuckets), func(i int) bool { return d <= DurationBuckets[i] })
+	m.Buckets[i]++
+}
+
+// Spans returns the finished spans in the order they ended
+func (t *Tracer) Spans() []Span {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	spans := make([]Span, len(t.spans))
+	for i, s := range t.spans {
+		spans[i] = *s
+	}
+	return spans
+}
+
+// Metrics returns the metrics of the finished spans sorted by name
+func (t *Tracer) Metrics() []Metric {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	metrics := make([]Metric, 0, len(t.metrics))
+	for _, m := range t.metrics {
+		metric := *m
+		metric.Buckets = append([]int64(nil), m.Buckets...)
+		metric.SumMs, metric.MinMs, metric.MaxMs = m.Sum.Milliseconds(), m.Min.Milliseconds(), m.Max.Milliseconds()
+		metrics = append(metrics, metric)
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Name < metrics[j].Name })
+	return metrics
+}
+
+func newID(size int) string {
+	b := make([]byte, size)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,124 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/splunk/acs-privateapps-demo/src/telemetry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpans(t *testing.T) {
+	assert := assert.New(t)
+	tracer := telemetry.NewTracer("cloudCtl")
+	root := tracer.Start("command", "command", "install")
+	child := tracer.Start("acs.InstallApp", "stack", "test-stack")
+	child.Finish(errors.New("409-conflict"))
+	sibling := tracer.Start("acs.RestartRequired")
+	sibling.Finish(nil)
+	root.Finish(nil)
+	root.Finish(nil)
+
+	spans := tracer.Spans()
+	assert.Len(spans, 3)
+	assert.Equal("acs.InstallApp", spans[0].Name)
+	assert.Equal(root.SpanID, spans[0].ParentID)
+	assert.Equal(root.SpanID, spans[1].ParentID)
+	assert.Empty(spans[2].ParentID)
+	assert.Equal(root.TraceID, spans[0].TraceID)
+	assert.Equal("test-stack", spans[0].Attributes["stack"])
+	assert.Equal("409-conflict", spans[0].Error)
+
+	metrics := tracer.Metrics()
+	assert.Len(metrics, 3)
+	assert.Equal("acs.InstallApp", metrics[0].Name)
+	assert.Equal(int64(1), metrics[0].Count)
+	assert.Equal(int64(1), metrics[0].Errors)
+	assert.Equal(int64(1), metrics[0].Buckets[0])
+}
+
+func TestNilTracer(t *testing.T) {
+	var tracer *telemetry.Tracer
+	span := tracer.Start("command")
+	span.SetAttribute("key", "value")
+	span.Finish(nil)
+	assert.Empty(t, tracer.Spans())
+}
+
+func TestWriteFile(t *testing.T) {
+	assert := assert.New(t)
+	tracer := telemetry.NewTracer("cloudCtl")
+	tracer.Start("command").Finish(nil)
+	path := filepath.Join(t.TempDir(), "trace.json")
+	assert.Nil(tracer.WriteFile(path))
+
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	trace := telemetry.Trace{}
+	assert.Nil(json.Unmarshal(data, &trace))
+	assert.Equal("cloudCtl", trace.Service)
+	assert.Len(trace.Spans, 1)
+	assert.Len(trace.Metrics, 1)
+}
+
+func TestExportOTLP(t *testing.T) {
+	assert := assert.New(t)
+	payloads := map[string]map[string]interface{}{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("application/json", r.Header.Get("Content-Type"))
+		payload := map[string]interface{}{}
+		assert.Nil(json.NewDecoder(r.Body).Decode(&payload))
+		payloads[r.URL.Path] = payload
+	}))
+	defer srv.Close()
+
+	tracer := telemetry.NewTracer("cloudCtl")
+	root := tracer.Start("command")
+	tracer.Start("appinspect.Submit", "victoria", true).Finish(nil)
+	root.Finish(errors.New("vetting failed"))
+	assert.Nil(tracer.ExportOTLP(nil, srv.URL+"/"))
+
+	resourceSpans := payloads["/v1/traces"]["resourceSpans"].([]interface{})
+	spans := resourceSpans[0].(map[string]interface{})["scopeSpans"].([]interface{})[0].(map[string]interface{})["spans"].([]interface{})
+	assert.Len(spans, 2)
+	submit := spans[0].(map[string]interface{})
+	assert.Equal("appinspect.Submit", submit["name"])
+	assert.Equal(root.SpanID, submit["parentSpanId"])
+	assert.Equal([]interface{}{map[string]interface{}{"key": "victoria", "value": map[string]interface{}{"boolValue": true}}},
+		submit["attributes"])
+	assert.Equal(float64(2), spans[1].(map[string]interface{})["status"].(map[string]interface{})["code"])
+
+	resourceMetrics := payloads["/v1/metrics"]["resourceMetrics"].([]interface{})
+	metrics := resourceMetrics[0].(map[string]interface{})["scopeMetrics"].([]interface{})[0].(map[string]interface{})["metrics"].([]interface{})
+	assert.Equal("span.duration", metrics[0].(map[string]interface{})["name"])
+	assert.Equal("span.errors", metrics[1].(map[string]interface{})["name"])
+}
+
+func TestExportOTLPFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	err := telemetry.NewTracer("cloudCtl").ExportOTLP(nil, srv.URL)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+}
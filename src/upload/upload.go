@@ -0,0 +1,275 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package upload streams app packages to the acs and appinspect services without loading them in memory.
+// Packages are hashed as they are sent and report their progress along the way.
+package upload
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// ProgressFunc is called as a package is read with the number of bytes sent so far, total is -1 when the
+// size of the package is not known
+type ProgressFunc func(sent, total int64)
+
+// Package is an app package which is hashed and reports its progress as it is read
+type Package struct {
+	name     string
+	reader   io.Reader
+	size     int64
+	sent     int64
+	hash     hash.Hash
+	progress ProgressFunc
+	closer   io.Closer
+}
+
+// New streams the package from reader, size is -1 when it is not known
+func New(name string, reader io.Reader, size int64) *Package {
+	return &Package{
+		name:   name,
+		reader: reader,
+		size:   size,
+		hash:   sha256.New(),
+	}
+}
+
+// Open streams the package from the file at path, which is closed along with the package
+func Open(path string) (*Package, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		f.Close()
+		return nil, fmt.Errorf("%s is a directory", path)
+	}
+	p := New(filepath.Base(path), f, info.Size())
+	p.closer = f
+	return p, nil
+}
+
+// OnProgress sets the function called as the package is read
+func (p *Package) OnProgress(progress ProgressFunc) *Package {
+	p.progress = progress
+	return p
+}
+
+// Name of the package file
+func (p *Package) Name() string {
+	return p.name
+}
+
+// Size of the package, -1 when it is not known
+func (p *Package) Size() int64 {
+	return p.size
+}
+
+// Read ...
+func (p *Package) Read(b []byte) (int, error) {
+	n, err := p.reader.Read(b)
+	if n > 0 {
+		p.hash.Write(b[:n])
+		p.sent += int64(n)
+		if p.progress != nil {
+			p.progress(p.sent, p.size)
+		}
+	}
+	return n, err
+}
+
+// SHA256 returns the hex encoded sha256 of the package, which is complete once it was read entirely
+func (p *Package) SHA256() string {
+	return hex.EncodeToString(p.hash.Sum(nil))
+}
+
+// Rewind starts the package over so it can be sent again, the reader must be an io.Seeker
+func (p *Package) Rewind() error {
+	s, ok := p.reader.(io.Seeker)
+	if !ok {
+		return fmt.Errorf("package %s cannot be rewound", p.name)
+	}
+	if _, err := s.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	p.sent = 0
+	p.hash.Reset()
+	return nil
+}
+
+// Close closes the file opened by Open
+func (p *Package) Close() error {
+	if p.closer == nil {
+		return nil
+	}
+	err := p.closer.Close()
+	p.closer = nil
+	return err
+}
+
+// Size returns the number of bytes left to read from reader, or -1 when it cannot be known without
+// reading it
+func Size(reader io.Reader) int64 {
+	switch r := reader.(type) {
+	case *Package:
+		if r.size < 0 {
+			return -1
+		}
+		return r.size - r.sent
+	case *Body:
+		return r.length
+	case interface{ Len() int }:
+		// bytes.Reader, bytes.Buffer and strings.Reader
+		return int64(r.Len())
+	case *os.File:
+		info, err := r.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - offset
+	}
+	return -1
+}
+
+// Body is a request body which is streamed by Stream, with a Content-Length header when its length is known
+type Body struct {
+	io.Reader
+	length int64
+}
+
+// NewBody streams reader as a request body
+func NewBody(reader io.Reader) *Body {
+	return &Body{Reader: reader, length: Size(reader)}
+}
+
+// NewSizedBody streams reader as a request body of the given length, -1 when it is not known
+func NewSizedBody(reader io.Reader, length int64) *Body {
+	return &Body{Reader: reader, length: length}
+}
+
+// Len returns the length of the body, -1 when it is not known
+func (b *Body) Len() int64 {
+	return b.length
+}
+
+// Close leaves the reader open, it belongs to the caller
+func (b *Body) Close() error {
+	return nil
+}
+
+// Multipart streams a multipart form with the fields and a file part named fileField reading from reader.
+// Only the part headers are held in memory, so the length of the form is known whenever the length of the
+// reader is.
+func Multipart(fields url.Values, fileField, fileName string, reader io.Reader) (*Body, string, error) {
+	var head bytes.Buffer
+	w := multipart.NewWriter(&head)
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range fields[k] {
+			if err := w.WriteField(k, v); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escapeQuotes(fileField),
+		escapeQuotes(fileName)))
+	h.Set("Content-Type", "application/octet-stream")
+	if _, err := w.CreatePart(h); err != nil {
+		return nil, "", err
+	}
+	var tail bytes.Buffer
+	tail.WriteString("\r\n--" + w.Boundary() + "--\r\n")
+
+	length := Size(reader)
+	if length >= 0 {
+		length += int64(head.Len() + tail.Len())
+	}
+	body := &Body{
+		Reader: io.MultiReader(&head, reader, &tail),
+		length: length,
+	}
+	return body, w.FormDataContentType(), nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+type bodyKey struct{}
+
+// Stream makes the request send body as it is read. resty reads io.Reader bodies in memory to be able to
+// send them again, so the body is handed over to StreamBody, which the client must have as its pre request
+// hook, through the context of the request instead.
+func Stream(r *resty.Request, body *Body) *resty.Request {
+	return r.SetContext(context.WithValue(r.Context(), bodyKey{}, body))
+}
+
+// Streamed reports whether the request streams its body, such requests cannot be sent again
+func Streamed(r *resty.Request) bool {
+	_, ok := r.Context().Value(bodyKey{}).(*Body)
+	return ok
+}
+
+// StreamBody is a resty pre request hook sending the body of requests prepared with Stream
+func StreamBody(_ *resty.Client, req *http.Request) error {
+	body, ok := req.Context().Value(bodyKey{}).(*Body)
+	if !ok {
+		return nil
+	}
+	req.GetBody = nil
+	if body.length == 0 {
+		req.Body = http.NoBody
+		req.ContentLength = 0
+		return nil
+	}
+	req.Body = body
+	// a zero length along with a body is sent chunked
+	req.ContentLength = 0
+	if body.length > 0 {
+		req.ContentLength = body.length
+	}
+	return nil
+}
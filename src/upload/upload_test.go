@@ -0,0 +1,123 @@
+// Copyright 2021 Splunk Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upload_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/splunk/acs-privateapps-demo/src/upload"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackage(t *testing.T) {
+	assert := assert.New(t)
+	data := bytes.Repeat([]byte("app"), 10000)
+	path := filepath.Join(t.TempDir(), "app.tar.gz")
+	assert.Nil(ioutil.WriteFile(path, data, 0644))
+
+	pkg, err := upload.Open(path)
+	assert.Nil(err)
+	defer pkg.Close()
+	assert.Equal("app.tar.gz", pkg.Name())
+	assert.Equal(int64(len(data)), pkg.Size())
+
+	var sent, total int64
+	pkg.OnProgress(func(s, t int64) { sent, total = s, t })
+	read, err := ioutil.ReadAll(pkg)
+	assert.Nil(err)
+	assert.Equal(data, read)
+	assert.Equal(int64(len(data)), sent)
+	assert.Equal(int64(len(data)), total)
+	sum := sha256.Sum256(data)
+	assert.Equal(hex.EncodeToString(sum[:]), pkg.SHA256())
+
+	assert.Nil(pkg.Rewind())
+	assert.Equal(int64(len(data)), upload.Size(pkg))
+	read, err = ioutil.ReadAll(pkg)
+	assert.Nil(err)
+	assert.Equal(data, read)
+	assert.Equal(hex.EncodeToString(sum[:]), pkg.SHA256())
+}
+
+func TestOpenDirectory(t *testing.T) {
+	_, err := upload.Open(t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestSize(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(int64(3), upload.Size(bytes.NewReader([]byte("app"))))
+	assert.Equal(int64(-1), upload.Size(ioutil.NopCloser(bytes.NewReader([]byte("app")))))
+
+	path := filepath.Join(t.TempDir(), "app.tar.gz")
+	assert.Nil(ioutil.WriteFile(path, []byte("app"), 0644))
+	f, err := os.Open(path)
+	assert.Nil(err)
+	defer f.Close()
+	assert.Equal(int64(3), upload.Size(f))
+}
+
+func TestMultipartStreamsWithLength(t *testing.T) {
+	assert := assert.New(t)
+	data := bytes.Repeat([]byte("app"), 10000)
+	var length int64
+	var token string
+	var received []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		length = r.ContentLength
+		token = r.FormValue("token")
+		f, _, err := r.FormFile("package")
+		if assert.Nil(err) {
+			received, _ = ioutil.ReadAll(f)
+		}
+	}))
+	defer srv.Close()
+
+	body, contentType, err := upload.Multipart(url.Values{"token": {"splunkbase-token"}}, "package", "app.tar.gz",
+		upload.New("app.tar.gz", bytes.NewReader(data), int64(len(data))))
+	assert.Nil(err)
+	assert.True(body.Len() > int64(len(data)))
+	_, err = upload.Stream(resty.New().SetPreRequestHook(upload.StreamBody).R(), body).
+		SetHeader("Content-Type", contentType).Post(srv.URL)
+	assert.Nil(err)
+	assert.Equal(body.Len(), length)
+	assert.Equal("splunkbase-token", token)
+	assert.Equal(data, received)
+}
+
+func TestUnknownLengthIsChunked(t *testing.T) {
+	assert := assert.New(t)
+	var length int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		length = r.ContentLength
+	}))
+	defer srv.Close()
+
+	body := upload.NewBody(ioutil.NopCloser(bytes.NewReader([]byte("app"))))
+	assert.Equal(int64(-1), body.Len())
+	_, err := upload.Stream(resty.New().SetPreRequestHook(upload.StreamBody).R(), body).Post(srv.URL)
+	assert.Nil(err)
+	assert.Equal(int64(-1), length)
+}